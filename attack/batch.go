@@ -0,0 +1,113 @@
+package attack
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions tunes ByteAtATimeECBBatched
+type BatchOptions struct {
+	GuessesPerQuery int // guess blocks packed in one query (all 256 when 0)
+	Workers         int // queries in flight at once (GOMAXPROCS when 0)
+}
+
+// ByteAtATimeECBBatched recovers the unknown bytes encrypt appends to our
+// input, like ByteAtATimeECB, with a fraction of the queries:
+//
+//   - ECB encrypts every block on its own, so the guess blocks for a byte
+//     (the 15 bytes before it and a guess) are sent side by side in one
+//     query, and read back as a dictionary from ciphertext block to guess.
+//   - The target blocks only depend on how many pad bytes go in front of
+//     the unknown bytes, so the blockSize queries that hold them all are
+//     made once, up front, and reused for every byte.
+//
+// That is about one query per byte instead of up to 257. Independent
+// queries, the target ones and the parts of a dictionary split by
+// opts.GuessesPerQuery, run on opts.Workers goroutines, so encrypt must be
+// safe for concurrent use.
+func ByteAtATimeECBBatched(encrypt func([]byte) []byte, blockSize int, opts BatchOptions) []byte {
+	return byteAtATimeBatched(encrypt, blockSize, -1, opts)
+}
+
+// byteAtATimeBatched is ByteAtATimeECBBatched stopping after n bytes, or
+// when no guess matches if n is negative
+func byteAtATimeBatched(encrypt func([]byte) []byte, blockSize, n int, opts BatchOptions) []byte {
+	perQuery := opts.GuessesPerQuery
+	if perQuery < 1 || perQuery > 256 {
+		perQuery = 256
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// targets[pad] is the ciphertext with pad bytes before the unknown ones
+	targets := make([][]byte, blockSize)
+	parallel(blockSize, workers, func(pad int) {
+		targets[pad] = encrypt(bytes.Repeat([]byte("A"), pad))
+	})
+
+	queries := (256 + perQuery - 1) / perQuery
+	known := bytes.Repeat([]byte("A"), blockSize-1)
+	var recovered []byte
+	for n < 0 || len(recovered) < n {
+		k := len(recovered)
+		pad := blockSize - 1 - k%blockSize
+		start := k / blockSize * blockSize
+		if start+blockSize > len(targets[pad]) {
+			break
+		}
+		target := string(targets[pad][start : start+blockSize])
+
+		// the block ending in byte k starts with the blockSize-1 bytes
+		// before it, our pad bytes included
+		context := known[len(known)-(blockSize-1):]
+		guess := -1
+		var mu sync.Mutex
+		parallel(queries, workers, func(q int) {
+			first, last := q*perQuery, min((q+1)*perQuery, 256)
+			input := make([]byte, 0, (last-first)*blockSize)
+			for g := first; g < last; g++ {
+				input = append(append(input, context...), byte(g))
+			}
+			ct := encrypt(input)
+			for g := first; g < last; g++ {
+				i := (g - first) * blockSize
+				if string(ct[i:i+blockSize]) == target {
+					mu.Lock()
+					guess = g
+					mu.Unlock()
+				}
+			}
+		})
+		if guess < 0 {
+			break // no match: likely the end of the unknown bytes
+		}
+
+		recovered = append(recovered, byte(guess))
+		known = append(known, byte(guess))
+	}
+	return recovered
+}
+
+// parallel calls fn(0) to fn(n-1) on up to workers goroutines and waits
+// for them
+func parallel(n, workers int, fn func(int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,110 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/drio/cryptopals/oracle"
+)
+
+const lyrics = "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK"
+
+func TestByteAtATimeECBBatched(t *testing.T) {
+	expected, _ := base64.StdEncoding.DecodeString(lyrics)
+	encrypt, err := oracle.NewECBSuffix("YELLOW SUBMARINE", lyrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Same answer as ByteAtATimeECB", func(t *testing.T) {
+		sequential := ByteAtATimeECB(encrypt, 16)
+		for _, opts := range []BatchOptions{{}, {GuessesPerQuery: 1, Workers: 1}, {GuessesPerQuery: 100, Workers: 3}} {
+			if got := ByteAtATimeECBBatched(encrypt, 16, opts); !bytes.Equal(got, sequential) {
+				t.Errorf("%+v: expected %q, got %q", opts, sequential, got)
+			}
+		}
+	})
+
+	t.Run("Queries", func(t *testing.T) {
+		for _, perQuery := range []int{256, 64} {
+			var queries atomic.Int64
+			counted := func(input []byte) []byte {
+				queries.Add(1)
+				return encrypt(input)
+			}
+			got := byteAtATimeBatched(counted, 16, len(expected), BatchOptions{GuessesPerQuery: perQuery})
+			if !bytes.Equal(got, expected) {
+				t.Fatalf("expected %q, got %q", expected, got)
+			}
+			// the target queries, then a dictionary per byte
+			if want := 16 + len(expected)*256/perQuery; queries.Load() != int64(want) {
+				t.Errorf("%d guesses per query: expected %d queries, got %d", perQuery, want, queries.Load())
+			}
+		}
+	})
+
+	t.Run("Engine", func(t *testing.T) {
+		for seed := range int64(5) {
+			encrypt, err := oracle.NewRandomPrefixECBFrom(rand.New(rand.NewSource(seed)), lyrics)
+			if err != nil {
+				t.Fatal(err)
+			}
+			batched, err := BreakECBBatched(OracleFunc(encrypt), BatchOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain, err := BreakECB(OracleFunc(encrypt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(batched.Suffix, expected) || batched.PrefixLen != plain.PrefixLen {
+				t.Errorf("seed %d: wrong report %+v", seed, batched)
+			}
+			if batched.Queries*10 > plain.Queries {
+				t.Errorf("seed %d: %d batched queries against %d", seed, batched.Queries, plain.Queries)
+			}
+		}
+	})
+}
+
+func BenchmarkByteAtATimeECB(b *testing.B) {
+	encrypt, err := oracle.NewECBSuffix("YELLOW SUBMARINE", lyrics)
+	if err != nil {
+		b.Fatal(err)
+	}
+	n := 138 // bytes of lyrics
+
+	// report reports the oracle calls and the time per recovered byte
+	report := func(b *testing.B, queries *atomic.Int64) {
+		recovered := float64(b.N * n)
+		b.ReportMetric(float64(queries.Load())/recovered, "queries/byte")
+		b.ReportMetric(float64(b.Elapsed().Nanoseconds())/recovered, "ns/byte")
+	}
+	counted := func(queries *atomic.Int64) func([]byte) []byte {
+		return func(input []byte) []byte {
+			queries.Add(1)
+			return encrypt(input)
+		}
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		var queries atomic.Int64
+		for b.Loop() {
+			byteAtATime(counted(&queries), 16, n)
+		}
+		report(b, &queries)
+	})
+	for _, opts := range []BatchOptions{{Workers: 1}, {GuessesPerQuery: 32, Workers: 1}, {GuessesPerQuery: 32}} {
+		b.Run(fmt.Sprintf("Batched/guesses=%d/workers=%d", opts.GuessesPerQuery, opts.Workers), func(b *testing.B) {
+			var queries atomic.Int64
+			for b.Loop() {
+				byteAtATimeBatched(counted(&queries), 16, n, opts)
+			}
+			report(b, &queries)
+		})
+	}
+}
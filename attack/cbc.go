@@ -0,0 +1,76 @@
+package attack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrTargetTooLong is returned when the bytes to forge don't fit in a
+// block
+var ErrTargetTooLong = errors.New("attack: target is longer than a block")
+
+// DetectCBCPrefixLength finds how many bytes encrypt puts before our input
+// when it encrypts in CBC mode under a fixed key and IV. Two inputs that
+// end in different bytes encrypt alike up to the block holding that byte,
+// so the first block that differs tells where it landed: we grow the
+// input until that byte moves to the next block.
+func DetectCBCPrefixLength(encrypt func([]byte) []byte, blockSize int) (int, error) {
+	firstDiff := func(n int) int {
+		a := encrypt(append(bytes.Repeat([]byte("A"), n), 'B'))
+		b := encrypt(append(bytes.Repeat([]byte("A"), n), 'C'))
+		for i := 0; i+blockSize <= min(len(a), len(b)); i += blockSize {
+			if !bytes.Equal(a[i:i+blockSize], b[i:i+blockSize]) {
+				return i / blockSize
+			}
+		}
+		return -1
+	}
+
+	// a random IV per call makes every block differ, whatever the input
+	if !bytes.Equal(encrypt(nil), encrypt(nil)) {
+		return 0, ErrNoPrefix
+	}
+
+	start := firstDiff(0)
+	if start < 0 {
+		return 0, ErrNoPrefix
+	}
+	for n := 1; n <= blockSize; n++ {
+		if firstDiff(n) > start {
+			return start*blockSize + blockSize - n, nil
+		}
+	}
+	return 0, ErrNoPrefix
+}
+
+// CBCBitflip forges a ciphertext that decrypts to target somewhere in the
+// middle. In CBC each plaintext block is XORed with the previous
+// ciphertext block, so flipping a bit there flips the same bit of the
+// plaintext, at the cost of scrambling the block we flipped. We send a
+// block to sacrifice followed by filler in place of the target, aligned
+// on a block boundary, and flip the sacrificial block's ciphertext by
+// filler XOR target. The oracle never sees the target, so quoting it
+// doesn't help.
+func CBCBitflip(encrypt func([]byte) []byte, blockSize int, target []byte) ([]byte, error) {
+	if len(target) > blockSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTargetTooLong, len(target))
+	}
+	prefixLen, err := DetectCBCPrefixLength(encrypt, blockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pad := (blockSize - prefixLen%blockSize) % blockSize
+	filler := bytes.Repeat([]byte("A"), len(target))
+	input := append(bytes.Repeat([]byte("A"), pad+blockSize), filler...)
+	cipherText := slices.Clone(encrypt(input))
+
+	// the sacrificial block starts where our aligned input does
+	flip := prefixLen + pad
+	for i := range target {
+		cipherText[flip+i] ^= filler[i] ^ target[i]
+	}
+	return cipherText, nil
+}
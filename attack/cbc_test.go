@@ -0,0 +1,73 @@
+package attack
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/oracle"
+)
+
+func TestCBCBitflip(t *testing.T) {
+	target := []byte(";admin=true;")
+
+	t.Run("Challenge 16", func(t *testing.T) {
+		for seed := range int64(10) {
+			ud := oracle.NewUserDataFrom(rand.New(rand.NewSource(seed)))
+			encrypt := func(input []byte) []byte { return ud.Encrypt(string(input)) }
+
+			if admin, _ := ud.IsAdmin(encrypt(target)); admin {
+				t.Fatalf("the service lets the target through unquoted")
+			}
+			forged, err := CBCBitflip(encrypt, 16, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			admin, err := ud.IsAdmin(forged)
+			if err != nil || !admin {
+				t.Errorf("seed %d: the forged cookie is not admin: %v", seed, err)
+			}
+		}
+	})
+
+	t.Run("Every prefix length", func(t *testing.T) {
+		key := []byte("YELLOW SUBMARINE")
+		iv := make([]byte, 16)
+		for n := range 40 {
+			prefix := bytes.Repeat([]byte{'p'}, n)
+			encrypt := func(input []byte) []byte {
+				plain := append(append(append([]byte{}, prefix...), input...), ";tail"...)
+				return modes.MustEncryptCBC(plain, key, iv)
+			}
+
+			if got, err := DetectCBCPrefixLength(encrypt, 16); err != nil || got != n {
+				t.Errorf("prefix %d: detected %d, %v", n, got, err)
+			}
+			forged, err := CBCBitflip(encrypt, 16, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain := modes.MustDecryptCBC(forged, key, iv)
+			if !bytes.Contains(plain, target) {
+				t.Errorf("prefix %d: target missing from %q", n, plain)
+			}
+		}
+	})
+
+	t.Run("Random IV", func(t *testing.T) {
+		encrypt := func(input []byte) []byte {
+			return modes.MustEncryptCBC(input, oracle.RandomAESKey(), oracle.RandomAESKey())
+		}
+		if _, err := CBCBitflip(encrypt, 16, target); !errors.Is(err, ErrNoPrefix) {
+			t.Errorf("expected ErrNoPrefix, got %v", err)
+		}
+	})
+
+	t.Run("Target too long", func(t *testing.T) {
+		if _, err := CBCBitflip(nil, 16, make([]byte, 17)); !errors.Is(err, ErrTargetTooLong) {
+			t.Errorf("expected ErrTargetTooLong, got %v", err)
+		}
+	})
+}
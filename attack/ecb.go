@@ -0,0 +1,207 @@
+// Package attack implements the attacks against the oracles in the
+// challenges.
+package attack
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+
+	"github.com/drio/cryptopals/oracle"
+	"github.com/drio/cryptopals/scan"
+)
+
+// ErrNoBlockSize is returned when the ciphertext never grows while we
+// feed the oracle longer inputs
+var ErrNoBlockSize = errors.New("attack: could not detect block size")
+
+// ErrNoPrefix is returned when no probe lines our input up with a block
+// boundary, as happens when the oracle isn't ECB
+var ErrNoPrefix = errors.New("attack: could not detect the prefix length")
+
+// ErrNoSuffixLength is returned when the ciphertext lengths don't add up
+// to a suffix, as happens when the oracle doesn't use PKCS#7 padding
+var ErrNoSuffixLength = errors.New("attack: could not detect the suffix length")
+
+// DetectMode guesses the mode (oracle.ECB or oracle.CBC) used to produce
+// cipherText: ECB when it repeats more 16 byte blocks than chance would
+// (see scan.AnalyzeECB). It expects the plaintext to contain repeated
+// blocks.
+func DetectMode(cipherText []byte) string {
+	if s, _ := scan.AnalyzeECB(cipherText, 16); s.Significant(scan.DefaultECBAlpha) {
+		return oracle.ECB
+	}
+	return oracle.CBC
+}
+
+// DetectBlockSize finds the block size used by encrypt by feeding it
+// growing inputs until the ciphertext grows
+func DetectBlockSize(encrypt func([]byte) []byte) (int, error) {
+	initialLen := len(encrypt([]byte{}))
+	for i := range 64 {
+		input := bytes.Repeat([]byte("A"), i)
+		newLen := len(encrypt(input))
+		if newLen > initialLen {
+			return newLen - initialLen, nil
+		}
+	}
+	return 0, ErrNoBlockSize
+}
+
+// IsECB confirms that encrypt runs in ECB mode
+func IsECB(encrypt func([]byte) []byte, blockSize int) bool {
+	input := bytes.Repeat([]byte("A"), blockSize*3) // 3 identical blocks
+	ct := encrypt(input)
+
+	// Break into blocks
+	seen := make(map[string]bool)
+	for i := 0; i+blockSize <= len(ct); i += blockSize {
+		block := string(ct[i : i+blockSize])
+		if seen[block] {
+			return true // duplicate found → ECB
+		}
+		seen[block] = true
+	}
+	return false
+}
+
+// ByteAtATimeECB recovers the unknown bytes encrypt appends to our input.
+// It exploits the deficiencies of AES in ECB mode. Mainly the fact that
+// the same 16-byte plaintext block always encrypts to the same
+// ciphertext block.
+func ByteAtATimeECB(encrypt func([]byte) []byte, blockSize int) []byte {
+	return byteAtATime(encrypt, blockSize, -1)
+}
+
+// byteAtATime is ByteAtATimeECB stopping after n bytes, or when no guess
+// matches if n is negative
+func byteAtATime(encrypt func([]byte) []byte, blockSize, n int) []byte {
+	var recovered []byte
+	for n < 0 || len(recovered) < n {
+		// Determine the block index we’re targeting
+		currentBlock := len(recovered) / blockSize
+
+		// Run the oracle with the appropriate padding so the byte we
+		// are trying to decrypt aligns with the end of the current
+		// block
+		bytesInBlock := len(recovered) % blockSize
+		numPaddingBytes := blockSize - 1 - bytesInBlock
+		padding := bytes.Repeat([]byte("A"), numPaddingBytes)
+		fullCiphertext := encrypt(padding)
+
+		// Let's now get the cipherText values for the block
+		// This will be our target block and we want to decrypt the
+		// last byte
+		start := currentBlock * blockSize
+		end := start + blockSize
+		if end > len(fullCiphertext) {
+			break // Oracle response is shorter than expected; probably done
+		}
+		targetBlock := fullCiphertext[start:end]
+
+		// Now we have our target block.
+		// We know all [0:blockSize-2] bytes we need to decrypt [0:blockSize-1]
+		// We know AES(ECB) is deterministic on its output giving the same input.
+		// So we can enumerate all possible 255 byte values (guess).
+		// We can generate a plainText that looks like:
+		// testInput = padding + recovered pt + guess
+		// Then we can run AES_ECB on that plainText (testInput) and compare the
+		// result to our targetBlock. If targetBlock and testBlock (output) match
+		// then we know our guess was correct and that byte is part of the original
+		// plain text.
+		var found bool
+		for guess := range 256 {
+			// Construct the input: padding + recovered + guess byte
+			testInput := slices.Clone(padding)
+			testInput = append(testInput, recovered...)
+			testInput = append(testInput, byte(guess))
+
+			// Get ciphertext for this input
+			testCiphertext := encrypt(testInput)
+			testBlock := testCiphertext[start:end]
+
+			if bytes.Equal(testBlock, targetBlock) {
+				recovered = append(recovered, byte(guess))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			break // No match found — likely end of the unknown string
+		}
+	}
+
+	return recovered
+}
+
+// DetectPrefixLength finds how many bytes encrypt puts before our input.
+// It sends pad bytes and two blocks of filler, for pad from 0 up, until two
+// consecutive ciphertext blocks are equal: the filler then starts a block,
+// right after prefix and pad. The probe is run with two fillers and the
+// blocks have to be equal under both and differ between them, so the
+// oracle's own bytes can't pass for ours.
+func DetectPrefixLength(encrypt func([]byte) []byte, blockSize int) (int, error) {
+	probe := func(pad int, filler byte) []byte {
+		return encrypt(bytes.Repeat([]byte{filler}, pad+2*blockSize))
+	}
+	block := func(ct []byte, i int) []byte {
+		return ct[i*blockSize : (i+1)*blockSize]
+	}
+
+	for pad := range blockSize {
+		a, b := probe(pad, 'A'), probe(pad, 'B')
+		if len(a) != len(b) {
+			return 0, ErrNoPrefix
+		}
+		for i := 0; (i+2)*blockSize <= len(a); i++ {
+			if bytes.Equal(block(a, i), block(a, i+1)) &&
+				bytes.Equal(block(b, i), block(b, i+1)) &&
+				!bytes.Equal(block(a, i), block(b, i)) {
+				return i*blockSize - pad, nil
+			}
+		}
+	}
+	return 0, ErrNoPrefix
+}
+
+// ByteAtATimeECBPrefix recovers the unknown bytes encrypt appends to our
+// input when it also puts bytes of its own before it: it pads the prefix
+// up to a block boundary, drops the blocks before our input and hands the
+// rest to ByteAtATimeECB, stopping at the length of the unknown bytes so
+// the padding is left out.
+func ByteAtATimeECBPrefix(encrypt func([]byte) []byte, blockSize int) ([]byte, error) {
+	prefixLen, err := DetectPrefixLength(encrypt, blockSize)
+	if err != nil {
+		return nil, err
+	}
+	suffixLen, err := detectSuffixLength(encrypt, blockSize, prefixLen)
+	if err != nil {
+		return nil, err
+	}
+
+	pad := bytes.Repeat([]byte("A"), (blockSize-prefixLen%blockSize)%blockSize)
+	skip := prefixLen + len(pad)
+	aligned := func(input []byte) []byte {
+		return encrypt(append(slices.Clone(pad), input...))[skip:]
+	}
+	return byteAtATime(aligned, blockSize, suffixLen), nil
+}
+
+// detectSuffixLength finds how many bytes encrypt puts after our input,
+// given the prefix length. The ciphertext grows a block once prefix,
+// input and suffix fill the last one, as the PKCS#7 padding then takes a
+// block of its own; an oracle that doesn't pad that way gives
+// ErrNoSuffixLength.
+func detectSuffixLength(encrypt func([]byte) []byte, blockSize, prefixLen int) (int, error) {
+	base := len(encrypt(nil))
+	for n := 1; n <= blockSize; n++ {
+		if len(encrypt(bytes.Repeat([]byte("A"), n))) > base {
+			if suffixLen := base - prefixLen - n; suffixLen >= 0 {
+				return suffixLen, nil
+			}
+			break
+		}
+	}
+	return 0, ErrNoSuffixLength
+}
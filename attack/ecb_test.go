@@ -0,0 +1,103 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/oracle"
+)
+
+func TestByteAtATimeECB(t *testing.T) {
+	secret := "Um9sbGluJyBpbiBteSA1LjAK" // "Rollin' in my 5.0\n"
+	encrypt, err := oracle.NewECBSuffix("YELLOW SUBMARINE", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blockSize, err := DetectBlockSize(encrypt)
+	if err != nil || blockSize != 16 {
+		t.Fatalf("expected block size 16, got %d (%v)", blockSize, err)
+	}
+
+	if !IsECB(encrypt, blockSize) {
+		t.Fatalf("expected the oracle to be detected as ECB")
+	}
+
+	got := ByteAtATimeECB(encrypt, blockSize)
+	if !bytes.HasPrefix(got, []byte("Rollin' in my 5.0\n")) {
+		t.Errorf("wrong recovered plaintext: %q", got)
+	}
+}
+
+func TestDetectMode(t *testing.T) {
+	plainText := bytes.Repeat([]byte("A"), 128)
+	for range 20 {
+		cipherText, mode := oracle.AES(plainText)
+		if got := DetectMode(cipherText); got != mode {
+			t.Errorf("expected %s, got %s", mode, got)
+		}
+	}
+}
+
+func TestByteAtATimeECBPrefix(t *testing.T) {
+	secret := []byte("Rollin' in my 5.0\nWith my rag-top down so my hair can blow\n")
+	key := []byte("YELLOW SUBMARINE")
+	rng := rand.New(rand.NewSource(1))
+
+	check := func(t *testing.T, prefix, secret []byte) {
+		t.Helper()
+		encrypt, err := oracle.NewECBPrefixSuffix(key, prefix, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, err := DetectPrefixLength(encrypt, 16); err != nil || n != len(prefix) {
+			t.Errorf("prefix of %d: detected %d (%v)", len(prefix), n, err)
+		}
+		got, err := ByteAtATimeECBPrefix(encrypt, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("prefix of %d: recovered %q", len(prefix), got)
+		}
+	}
+
+	t.Run("Every prefix length", func(t *testing.T) {
+		for n := range 65 {
+			prefix := make([]byte, n)
+			rng.Read(prefix)
+			check(t, prefix, secret)
+		}
+	})
+
+	t.Run("Prefix and secret that look like the probes", func(t *testing.T) {
+		check(t, bytes.Repeat([]byte("A"), 21), secret)
+		check(t, bytes.Repeat([]byte("B"), 37), secret)
+		check(t, make([]byte, 48), secret) // three equal blocks
+		check(t, []byte("xyz"), append(bytes.Repeat([]byte("A"), 20), secret...))
+	})
+
+	t.Run("Random oracle", func(t *testing.T) {
+		encrypt, err := oracle.NewRandomPrefixECB(base64.StdEncoding.EncodeToString(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ByteAtATimeECBPrefix(encrypt, 16)
+		if err != nil || !bytes.Equal(got, secret) {
+			t.Errorf("recovered %q, %v", got, err)
+		}
+	})
+
+	t.Run("Not ECB", func(t *testing.T) {
+		encrypt := func(input []byte) []byte {
+			return modes.MustEncryptCBC(append([]byte("prefix"), input...), key, make([]byte, 16))
+		}
+		if _, err := DetectPrefixLength(encrypt, 16); !errors.Is(err, ErrNoPrefix) {
+			t.Errorf("expected ErrNoPrefix, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package attack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/drio/cryptopals/oracle"
+)
+
+var (
+	// ErrNotECB is returned when an oracle doesn't encrypt equal blocks
+	// alike
+	ErrNotECB = errors.New("attack: oracle is not ECB")
+	// ErrIncomplete is returned when no guess matches a suffix byte, as
+	// happens when the oracle changes its suffix between queries
+	ErrIncomplete = errors.New("attack: suffix recovery stopped early")
+)
+
+// Oracle is an encryption service we can feed input to. It adds secrets
+// of its own around the input, under a key it keeps.
+type Oracle interface {
+	Encrypt(input []byte) []byte
+}
+
+// OracleFunc adapts an encrypt function, like the ones package oracle
+// returns, to Oracle
+type OracleFunc func([]byte) []byte
+
+// Encrypt calls f(input)
+func (f OracleFunc) Encrypt(input []byte) []byte {
+	return f(input)
+}
+
+// ECBReport is what BreakECB learned about an oracle
+type ECBReport struct {
+	BlockSize int
+	Mode      string // oracle.ECB, or empty when the oracle is not ECB
+	PrefixLen int    // bytes the oracle puts before our input
+	SuffixLen int    // bytes the oracle puts after our input
+	Suffix    []byte // the bytes after our input
+	Queries   int    // calls to the oracle's Encrypt
+}
+
+// BreakECB runs the byte-at-a-time attack against o from scratch: it works
+// out the block size, checks the mode is ECB, finds the prefix and suffix
+// lengths and recovers the suffix. The report counts the queries that
+// took. When a step fails the report holds what was learned before it.
+func BreakECB(o Oracle) (*ECBReport, error) {
+	return breakECB(o, byteAtATime)
+}
+
+// BreakECBBatched is like BreakECB but recovers the suffix with
+// ByteAtATimeECBBatched, for far fewer queries. o must be safe for
+// concurrent use.
+func BreakECBBatched(o Oracle, opts BatchOptions) (*ECBReport, error) {
+	return breakECB(o, func(encrypt func([]byte) []byte, blockSize, n int) []byte {
+		return byteAtATimeBatched(encrypt, blockSize, n, opts)
+	})
+}
+
+// breakECB implements BreakECB with breakSuffix recovering the suffix
+func breakECB(o Oracle, breakSuffix func(encrypt func([]byte) []byte, blockSize, n int) []byte) (*ECBReport, error) {
+	r := &ECBReport{}
+	var queries atomic.Int64
+	defer func() { r.Queries = int(queries.Load()) }()
+	encrypt := func(input []byte) []byte {
+		queries.Add(1)
+		return o.Encrypt(input)
+	}
+
+	blockSize, err := DetectBlockSize(encrypt)
+	if err != nil {
+		return r, err
+	}
+	r.BlockSize = blockSize
+
+	if !IsECB(encrypt, blockSize) {
+		return r, ErrNotECB
+	}
+	r.Mode = oracle.ECB
+
+	if r.PrefixLen, err = DetectPrefixLength(encrypt, blockSize); err != nil {
+		return r, err
+	}
+
+	if r.SuffixLen, err = detectSuffixLength(encrypt, blockSize, r.PrefixLen); err != nil {
+		return r, err
+	}
+
+	pad := bytes.Repeat([]byte("A"), (blockSize-r.PrefixLen%blockSize)%blockSize)
+	skip := r.PrefixLen + len(pad)
+	aligned := func(input []byte) []byte {
+		return encrypt(append(slices.Clone(pad), input...))[skip:]
+	}
+	r.Suffix = breakSuffix(aligned, blockSize, r.SuffixLen)
+	if len(r.Suffix) < r.SuffixLen {
+		return r, fmt.Errorf("%w: %d of %d bytes", ErrIncomplete, len(r.Suffix), r.SuffixLen)
+	}
+	return r, nil
+}
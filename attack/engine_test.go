@@ -0,0 +1,98 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/oracle"
+)
+
+// cookieService is an ECB service written against Oracle directly
+type cookieService struct {
+	key, prefix, suffix []byte
+}
+
+func (c cookieService) Encrypt(input []byte) []byte {
+	plain := append(append(append([]byte{}, c.prefix...), input...), c.suffix...)
+	return modes.MustEncryptECB(plain, c.key)
+}
+
+func TestBreakECB(t *testing.T) {
+	secret := "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwo="
+	expected, _ := base64.StdEncoding.DecodeString(secret)
+
+	t.Run("Challenge 12", func(t *testing.T) {
+		encrypt, err := oracle.NewECBSuffix("YELLOW SUBMARINE", secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := BreakECB(OracleFunc(encrypt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.BlockSize != 16 || r.Mode != oracle.ECB || r.PrefixLen != 0 || r.SuffixLen != len(expected) {
+			t.Errorf("wrong report %+v", r)
+		}
+		if !bytes.Equal(r.Suffix, expected) {
+			t.Errorf("expected %q, got %q", expected, r.Suffix)
+		}
+		// at least a query per byte, at most 257
+		if r.Queries < len(expected) || r.Queries > 300*len(expected) {
+			t.Errorf("implausible query count %d", r.Queries)
+		}
+	})
+
+	t.Run("Challenge 14", func(t *testing.T) {
+		for seed := range int64(10) {
+			encrypt, err := oracle.NewRandomPrefixECBFrom(rand.New(rand.NewSource(seed)), secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := BreakECB(OracleFunc(encrypt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(r.Suffix, expected) {
+				t.Errorf("seed %d: expected %q, got %q", seed, expected, r.Suffix)
+			}
+		}
+	})
+
+	t.Run("Other services", func(t *testing.T) {
+		for _, c := range []cookieService{
+			{[]byte("YELLOW SUBMARINE"), []byte("user="), []byte(";role=user")},
+			{oracle.RandomAESKey(), bytes.Repeat([]byte("p"), 16), bytes.Repeat([]byte{0}, 16)},
+			{oracle.RandomAESKey(), []byte("prefix only"), nil},
+		} {
+			r, err := BreakECB(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.PrefixLen != len(c.prefix) || r.SuffixLen != len(c.suffix) || !bytes.Equal(r.Suffix, c.suffix) {
+				t.Errorf("%q/%q: wrong report %+v", c.prefix, c.suffix, r)
+			}
+		}
+	})
+
+	t.Run("No padding", func(t *testing.T) {
+		identity := OracleFunc(func(input []byte) []byte { return bytes.Clone(input) })
+		r, err := BreakECB(identity)
+		if !errors.Is(err, ErrNoSuffixLength) || r.SuffixLen != 0 {
+			t.Errorf("expected ErrNoSuffixLength, got %+v, %v", r, err)
+		}
+	})
+
+	t.Run("CBC", func(t *testing.T) {
+		key, iv := oracle.RandomAESKey(), make([]byte, 16)
+		r, err := BreakECB(OracleFunc(func(input []byte) []byte {
+			return modes.MustEncryptCBC(append(input, expected...), key, iv)
+		}))
+		if !errors.Is(err, ErrNotECB) || r.Mode != "" || r.BlockSize != 16 {
+			t.Errorf("expected ErrNotECB and no mode, got %+v, %v", r, err)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Package block holds helpers to split byte slices into fixed size blocks
+// and to reason about them (transposition, duplicate detection).
+package block
+
+// Split returns byte blocks of blockSize from data. The last block is
+// shorter when len(data) is not a multiple of blockSize.
+func Split(data []byte, blockSize int) [][]byte {
+	var chunks [][]byte
+	for i := 0; i < len(data); i += blockSize {
+		end := min(i+blockSize, len(data))
+		chunks = append(chunks, data[i:end])
+	}
+	return chunks
+}
+
+// Transpose returns, given a list of blocks, another set of blocks that
+// contain the nth byte of each block
+// [ [ b11, b12] [b21, b22], ...] -> [ [b11, b21, ...], [b12, b22, ...]
+func Transpose(blocks [][]byte, kSize int) [][]byte {
+	tBlocks := [][]byte{}
+
+	for i := range kSize {
+		tmpBlock := []byte{}
+		for _, block := range blocks {
+			if i >= len(block) {
+				break
+			}
+			tmpBlock = append(tmpBlock, block[i])
+		}
+		tBlocks = append(tBlocks, tmpBlock)
+	}
+
+	return tBlocks
+}
+
+// CountDuplicates counts the number of blocks that are duplicates
+func CountDuplicates(blocks [][]byte) int {
+	mapDuplicates := make(map[string]int)
+	totalDupCount := 0
+
+	for _, block := range blocks {
+		mapDuplicates[string(block)] += 1
+	}
+
+	for _, count := range mapDuplicates {
+		if count > 1 {
+			totalDupCount += count - 1
+		}
+	}
+
+	return totalDupCount
+}
@@ -0,0 +1,202 @@
+// Command cryptopals runs the challenge solutions.
+//
+// Usage:
+//
+//	cryptopals list
+//	cryptopals run [-data dir] [-input file] [-part n] [-alts n] [-scorer name [-corpus file] | -lang spec] set2 12
+//	cryptopals run [-data dir] -all
+//	cryptopals verify [-data dir]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, `usage:
+  cryptopals list
+  cryptopals run [-data dir] [-input file] [-part n] [-alts n] [-scorer name [-corpus file] | -lang spec] <set> <challenge>
+  cryptopals run [-data dir] -all
+  cryptopals verify [-data dir]
+`)
+}
+
+// run dispatches the subcommand in args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	var err error
+	switch args[0] {
+	case "list":
+		err = cmdList(stdout)
+	case "run":
+		err = cmdRun(args[1:], stdout, stderr)
+	case "verify":
+		err = cmdVerify(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitFailure
+	}
+}
+
+func cmdList(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range challenges {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.id(), c.title, strings.Join(c.inputs, " "))
+	}
+	return tw.Flush()
+}
+
+// newFlagSet returns a flag set for the subcommand name with the flags
+// every subcommand shares
+func newFlagSet(name string, cfg *config, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.dataDir, "data", "", "directory with the challenge data files (default: the embedded ones)")
+	return fs
+}
+
+func cmdRun(args []string, stdout, stderr io.Writer) error {
+	cfg := &config{out: stdout}
+	fs := newFlagSet("run", cfg, stderr)
+	fs.StringVar(&cfg.input, "input", "", "input file (overrides the challenge default)")
+	fs.IntVar(&cfg.part, "part", 2, "part of the challenge to run (set1 6)")
+	fs.IntVar(&cfg.alts, "alts", 0, "list up to n runner-up keys when a XOR break is ambiguous (set1 3, 4, 6)")
+	fs.Int64Var(&cfg.seed, "seed", 0, "seed for the random oracles, random when 0 (set2 11, 14)")
+	fs.IntVar(&cfg.workers, "workers", 0, "goroutines scanning the ciphertext files, all CPUs when 0 (set1 4, 8)")
+	keySize := fs.String("keysize", "combined", "key size estimator (set1 6): "+estimatorNames)
+	fs.BoolVar(&cfg.magic, "magic", false, "break the XOR of a known file type (png, zip, elf, gzip, pdf) from its magic numbers (set1 6)")
+	all := fs.Bool("all", false, "run every challenge")
+	scorerName := fs.String("scorer", "frequency", "plaintext scoring model: "+scorerNames)
+	corpus := fs.String("corpus", "", "corpus file to train the scoring model on")
+	lang := fs.String("lang", "", `language profiles: "auto", or built-in names / JSON files separated by commas`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := newScorer(*scorerName, *corpus)
+	if err != nil {
+		return err
+	}
+	cfg.scorer = s
+	if cfg.estimator, err = newEstimator(*keySize); err != nil {
+		return err
+	}
+	if *lang != "" {
+		if cfg.profiles, err = newProfiles(*lang); err != nil {
+			return err
+		}
+		cfg.scorer = score.Auto(cfg.profiles)
+	}
+
+	if *all {
+		if fs.NArg() != 0 || cfg.input != "" {
+			fmt.Fprintln(stderr, "-all takes no challenge and no -input")
+			return errUsage
+		}
+		failed := 0
+		for _, c := range challenges {
+			fmt.Fprintf(stdout, "== %s: %s\n", c.id(), c.title)
+			if _, err := c.runWith(cfg); err != nil {
+				fmt.Fprintf(stderr, "%s: %s\n", c.id(), err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d challenge(s) failed", failed)
+		}
+		return nil
+	}
+
+	if fs.NArg() != 2 {
+		usage(stderr)
+		return errUsage
+	}
+	c, err := lookup(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if _, err := c.runWith(cfg); err != nil {
+		return fmt.Errorf("%s: %w", c.id(), err)
+	}
+	return nil
+}
+
+// cmdVerify runs every challenge with its default options, checks the
+// output against the expected answer and prints a pass/fail table
+func cmdVerify(args []string, stdout, stderr io.Writer) error {
+	cfg := &config{part: 2}
+	fs := newFlagSet("verify", cfg, stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		usage(stderr)
+		return errUsage
+	}
+
+	failed := 0
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tTITLE\tRESULT\t\n")
+	for _, c := range challenges {
+		result := "PASS"
+		if err := c.verify(cfg); err != nil {
+			result = "FAIL: " + truncate(err.Error(), 60)
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", c.id(), c.title, result)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d challenge(s) failed", failed, len(challenges))
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
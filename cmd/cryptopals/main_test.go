@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/xor"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("List", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"list"}, &stdout, &stderr); code != exitOK {
+			t.Fatalf("expected exit code %d, got %d", exitOK, code)
+		}
+		if !strings.Contains(stdout.String(), "set2/12") {
+			t.Errorf("list output is missing set2/12:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("Usage errors", func(t *testing.T) {
+		cases := [][]string{
+			{},
+			{"nope"},
+			{"run"},
+			{"run", "set1"},
+			{"run", "-all", "set1", "3"},
+		}
+		for _, args := range cases {
+			var stdout, stderr bytes.Buffer
+			if code := run(args, &stdout, &stderr); code != exitUsage {
+				t.Errorf("%q: expected exit code %d, got %d", args, exitUsage, code)
+			}
+		}
+	})
+
+	t.Run("Unknown challenge", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"run", "set9", "99"}, &stdout, &stderr); code != exitFailure {
+			t.Errorf("expected exit code %d, got %d", exitFailure, code)
+		}
+	})
+
+	t.Run("Missing data file", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		args := []string{"run", "-data", "does-not-exist", "set1", "6"}
+		if code := run(args, &stdout, &stderr); code != exitFailure {
+			t.Errorf("expected exit code %d, got %d", exitFailure, code)
+		}
+	})
+
+	t.Run("Data directory", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "set1"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		line := xor.MustRepeat([]byte("swapped in\n"), []byte{'X'})
+		if err := os.WriteFile(filepath.Join(dir, "set1", "4.txt"), []byte(codec.ToHex(line)+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"run", "-data", dir, "set1", "4"}, &stdout, &stderr); code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+		}
+		if stdout.String() != "swapped in\n" {
+			t.Errorf("expected the file under -data, got %q", stdout.String())
+		}
+	})
+
+	t.Run("Run a challenge", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		args := []string{"run", "2", "9"}
+		if code := run(args, &stdout, &stderr); code != exitOK {
+			t.Errorf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+		}
+	})
+}
+
+func TestScorerFlags(t *testing.T) {
+	cases := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"run", "-scorer", "chi2", "set1", "3"}, exitOK},
+		{[]string{"run", "-scorer", "trigram", "-corpus", "../../data/set1/output6.txt", "set1", "3"}, exitOK},
+		{[]string{"run", "-scorer", "trigram", "set1", "3"}, exitFailure},
+		{[]string{"run", "-scorer", "nope", "set1", "3"}, exitFailure},
+		{[]string{"run", "-keysize", "ic", "set1", "3"}, exitOK},
+		{[]string{"run", "-keysize", "nope", "set1", "3"}, exitFailure},
+	}
+	for _, tc := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(tc.args, &stdout, &stderr); code != tc.code {
+			t.Errorf("%q: expected exit code %d, got %d: %s", tc.args, tc.code, code, stderr.String())
+		}
+		if tc.code == exitOK && !strings.Contains(stdout.String(), "Cooking MC's like a pound of bacon") {
+			t.Errorf("%q: wrong output %q", tc.args, stdout.String())
+		}
+	}
+}
+
+func TestLangFlag(t *testing.T) {
+	plainText, err := os.ReadFile("../../score/testdata/spanish.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipherText := xor.MustRepeat(plainText, []byte("Contrasena"))
+	input := filepath.Join(t.TempDir(), "spanish.txt")
+	if err := os.WriteFile(input, []byte(base64.StdEncoding.EncodeToString(cipherText)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"run", "-lang", "auto", "-input", input, "set1", "6"}
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	expected := "language: spanish\n" + string(plainText) + "\n"
+	if stdout.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+
+	if code := run([]string{"run", "-lang", "klingon", "set1", "3"}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("expected exit code %d for an unknown language, got %d", exitFailure, code)
+	}
+}
+
+func TestMagicFlag(t *testing.T) {
+	// a gzip file
+	plainText := []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03\xcb\x48\xcd\xc9\xc9\x07\x00\x86\xa6\x10\x36\x05\x00\x00\x00")
+	input := filepath.Join(t.TempDir(), "hello.gz.b64")
+	cipherText := xor.MustRepeat(plainText, []byte("key"))
+	if err := os.WriteFile(input, []byte(base64.StdEncoding.EncodeToString(cipherText)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"run", "-magic", "-input", input, "set1", "6"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if expected := "type: gzip\nkey: 6b6579\n"; stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestAltsFlag(t *testing.T) {
+	// a single letter can't tell its case apart
+	input := filepath.Join(t.TempDir(), "4.txt")
+	if err := os.WriteFile(input, []byte("3a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"run", "-input", input, "set1", "4"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "ambiguous") {
+		t.Errorf("alternatives listed without -alts:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"run", "-alts", "2", "-input", input, "set1", "4"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ambiguous") || strings.Count(stdout.String(), "\n  0x") != 3 {
+		t.Errorf("expected the winner and 2 alternatives, got:\n%s", stdout.String())
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, set := range []string{"set2", "2"} {
+		c, err := lookup(set, "12")
+		if err != nil {
+			t.Fatalf("lookup(%q, 12): %v", set, err)
+		}
+		if c.id() != "set2/12" {
+			t.Errorf("expected set2/12, got %s", c.id())
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("Every challenge passes", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"verify"}, &stdout, &stderr); code != exitOK {
+			t.Fatalf("expected exit code %d, got %d\n%s%s", exitOK, code, stdout.String(), stderr.String())
+		}
+		if strings.Contains(stdout.String(), "FAIL") {
+			t.Errorf("unexpected failure:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("Checkers catch wrong output", func(t *testing.T) {
+		cfg := &config{}
+		c := challenge{1, 5, "Implement repeating-key XOR", nil, runSet1Ch5, equals("nope")}
+		if err := c.verify(cfg); err == nil {
+			t.Errorf("expected the checker to fail")
+		}
+
+		c.check = matchesFile("set1/output6.txt")
+		if err := c.verify(cfg); err == nil {
+			t.Errorf("expected the checker to fail")
+		}
+	})
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files under testdata")
+
+// TestGolden compares the output of every challenge runner with its
+// golden file under testdata. Run with -update after changing an output
+// on purpose.
+func TestGolden(t *testing.T) {
+	type golden struct {
+		name string
+		args []string
+	}
+	runs := []golden{}
+	for _, c := range challenges {
+		args := []string{"run", "-seed", "1", strconv.Itoa(c.set), strconv.Itoa(c.num)}
+		runs = append(runs, golden{c.id(), args})
+	}
+	runs = append(runs, golden{"set1/6-part1", []string{"run", "-part", "1", "set1", "6"}})
+
+	for _, g := range runs {
+		t.Run(g.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(g.args, &stdout, &stderr); code != exitOK {
+				t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+			}
+
+			fn := filepath.Join("testdata", g.name+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(fn), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(fn, stdout.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(fn)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(stdout.Bytes(), expected) {
+				t.Errorf("output differs from %s\nexpected:\n%s\ngot:\n%s", fn, expected, stdout.String())
+			}
+		})
+	}
+}
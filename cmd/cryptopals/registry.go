@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/drio/cryptopals/data"
+	"github.com/drio/cryptopals/oracle"
+	"github.com/drio/cryptopals/score"
+	"github.com/drio/cryptopals/xor"
+)
+
+// config holds the options shared by the challenge runners
+type config struct {
+	dataDir string    // directory with the challenge data files (the embedded ones when empty)
+	input   string    // when set, overrides the first challenge input file
+	part    int       // runSet1Ch6: 1 prints the key size table, 2 breaks it
+	workers int       // size of the worker pool of the corpus scans (set1 4, 8)
+	alts    int       // runner-up keys to list when a single-byte XOR break is ambiguous
+	seed    int64     // seeds the random oracles when not 0 (set2 11, 14)
+	out     io.Writer // where the runners write their output
+
+	// scorer rates candidate plaintexts in the XOR challenges (the
+	// English frequency model when nil)
+	scorer score.Scorer
+	// profiles, when set, make the XOR challenges pick the language of
+	// the plaintext among them
+	profiles []*score.Profile
+	// magic makes runSet1Ch6 look for a known file type instead of text
+	magic bool
+	// estimator ranks the repeating-key XOR key sizes (set1 6)
+	estimator xor.KeySizeEstimator
+
+	inputs []string // input files of the challenge being run
+}
+
+// dataset returns the challenge data files: the embedded ones, or the
+// files under dataDir when set
+func (c *config) dataset() *data.Dataset {
+	if c.dataDir == "" {
+		return data.Default
+	}
+	return data.New(os.DirFS(c.dataDir))
+}
+
+// inputName names the i-th input file of the challenge in the errors
+func (c *config) inputName(i int) string {
+	if i == 0 && c.input != "" {
+		return c.input
+	}
+	return c.inputs[i]
+}
+
+// open opens the i-th input file of the challenge
+func (c *config) open(i int) (io.ReadCloser, error) {
+	if i == 0 && c.input != "" {
+		return os.Open(c.input)
+	}
+	return c.dataset().Open(c.inputs[i])
+}
+
+// readInput returns the content of the i-th input file of the challenge
+func (c *config) readInput(i int) ([]byte, error) {
+	f, err := c.open(i)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// checker validates the answer a runner returned
+type checker func(answer string, cfg *config) error
+
+// challenge is an entry in the registry of runners
+type challenge struct {
+	set    int
+	num    int
+	title  string
+	inputs []string                      // data files (names in the dataset)
+	run    func(*config) (string, error) // prints its work to cfg.out, returns the answer
+	check  checker
+}
+
+func (c challenge) id() string {
+	return fmt.Sprintf("set%d/%d", c.set, c.num)
+}
+
+// runWith runs the challenge with a copy of cfg that knows its inputs and
+// returns its answer
+func (c challenge) runWith(cfg *config) (string, error) {
+	rc := *cfg
+	rc.inputs = c.inputs
+	return c.run(&rc)
+}
+
+// verify runs the challenge, discarding its output, and checks its answer
+func (c challenge) verify(cfg *config) error {
+	rc := *cfg
+	rc.out = io.Discard
+	answer, err := c.runWith(&rc)
+	if err != nil {
+		return err
+	}
+	return c.check(answer, &rc)
+}
+
+var challenges = []challenge{
+	{1, 3, "Single-byte XOR cipher", nil, runSet1Ch3,
+		equals("Cooking MC's like a pound of bacon")},
+	{1, 4, "Detect single-character XOR", []string{"set1/4"}, runSet1Ch4,
+		equals("Now that the party is jumping\n")},
+	{1, 5, "Implement repeating-key XOR", nil, runSet1Ch5,
+		equals("0b3637272a2b2e63622c2e69692a23693a2a3c6324202d623d63343c2a26226324272765272a282b2f20430a652e2c652a3124333a653e2b2027630c692b20283165286326302e27282f")},
+	{1, 6, "Break repeating-key XOR", []string{"set1/6"}, runSet1Ch6,
+		matchesFile("set1/output6")},
+	{1, 7, "AES in ECB mode", []string{"set1/7"}, runSet1Ch7,
+		matchesFile("set1/output6")},
+	{1, 8, "Detect AES in ECB mode", []string{"set1/8"}, runSet1Ch8,
+		contains("d880619740a8a19b7840a8a31c810a3d")},
+	{2, 9, "Implement PKCS#7 padding", nil, runSet2Ch09,
+		equals("YELLOW SUBMARINE\x04\x04\x04\x04")},
+	{2, 10, "Implement CBC mode", nil, runSet2Ch10,
+		equals("This is a very important secret and should never shared with anyone")},
+	{2, 11, "An ECB/CBC detection oracle", nil, runSet2Ch11,
+		oneOf(oracle.ECB, oracle.CBC)},
+	{2, 12, "Byte-at-a-time ECB decryption (Simple)", nil, runSet2Ch12,
+		contains("Rollin' in my 5.0\nWith my rag-top down so my hair can blow\n")},
+	{2, 13, "ECB cut-and-paste", nil, runSet2Ch13,
+		equals("admin")},
+	{2, 14, "Byte-at-a-time ECB decryption (Harder)", nil, runSet2Ch14,
+		contains("Rollin' in my 5.0\nWith my rag-top down so my hair can blow\n")},
+	{2, 15, "PKCS#7 padding validation", nil, runSet2Ch15,
+		equals("ICE ICE BABY")},
+	{2, 16, "CBC bitflipping attacks", nil, runSet2Ch16,
+		equals("true")},
+}
+
+// equals checks that the answer is exactly expected
+func equals(expected string) checker {
+	return func(answer string, cfg *config) error {
+		if answer != expected {
+			return fmt.Errorf("expected %q, got %q", expected, answer)
+		}
+		return nil
+	}
+}
+
+// contains checks that expected is part of the answer
+func contains(expected string) checker {
+	return func(answer string, cfg *config) error {
+		if !strings.Contains(answer, expected) {
+			return fmt.Errorf("answer does not contain %q", expected)
+		}
+		return nil
+	}
+}
+
+// oneOf checks that the answer is one of valid
+func oneOf(valid ...string) checker {
+	return func(answer string, cfg *config) error {
+		if !slices.Contains(valid, answer) {
+			return fmt.Errorf("expected one of %q, got %q", valid, answer)
+		}
+		return nil
+	}
+}
+
+// matchesFile checks the answer against the content of a data file
+// (ignoring trailing new lines)
+func matchesFile(name string) checker {
+	return func(answer string, cfg *config) error {
+		expected, err := cfg.dataset().ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if strings.TrimRight(answer, "\n") != strings.TrimRight(string(expected), "\n") {
+			return fmt.Errorf("answer does not match %s", name)
+		}
+		return nil
+	}
+}
+
+// lookup finds a challenge given its set ("set2" or "2") and number
+func lookup(set, num string) (challenge, error) {
+	s, err := strconv.Atoi(strings.TrimPrefix(set, "set"))
+	if err != nil {
+		return challenge{}, fmt.Errorf("invalid set %q", set)
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return challenge{}, fmt.Errorf("invalid challenge %q", num)
+	}
+
+	for _, c := range challenges {
+		if c.set == s && c.num == n {
+			return c, nil
+		}
+	}
+	return challenge{}, fmt.Errorf("no runner for set %d challenge %d", s, n)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/drio/cryptopals/score"
+	"github.com/drio/cryptopals/xor"
+)
+
+// scorerNames lists the values accepted by -scorer
+const scorerNames = "frequency, chi2, bigram, trigram, words"
+
+// newScorer builds the plaintext scoring model name. When corpus is set
+// the model is trained on it; bigram and trigram need one.
+func newScorer(name, corpus string) (score.Scorer, error) {
+	if corpus == "" {
+		switch name {
+		case "frequency":
+			return score.English, nil
+		case "chi2":
+			return score.NewChiSquared(score.EnglishFrequencies), nil
+		case "words":
+			return score.NewWordList(score.EnglishWords), nil
+		case "bigram", "trigram":
+			return nil, fmt.Errorf("scorer %q needs -corpus", name)
+		}
+		return nil, fmt.Errorf("unknown scorer %q (want one of %s)", name, scorerNames)
+	}
+
+	f, err := os.Open(corpus)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch name {
+	case "chi2":
+		return score.TrainChiSquared(f)
+	case "bigram":
+		return score.TrainNGram(f, 2)
+	case "trigram":
+		return score.TrainNGram(f, 3)
+	case "words":
+		return score.TrainWordList(f, 1)
+	case "frequency":
+		return nil, fmt.Errorf("scorer %q is not trainable", name)
+	}
+	return nil, fmt.Errorf("unknown scorer %q (want one of %s)", name, scorerNames)
+}
+
+// newProfiles parses the value of -lang: "auto" for every built-in
+// profile, or a comma separated list of built-in names and JSON profile
+// files
+func newProfiles(spec string) ([]*score.Profile, error) {
+	if spec == "auto" {
+		return score.Profiles(), nil
+	}
+
+	profiles := []*score.Profile{}
+	for _, name := range strings.Split(spec, ",") {
+		if !strings.HasSuffix(name, ".json") {
+			p, err := score.Builtin(name)
+			if err != nil {
+				return nil, err
+			}
+			profiles = append(profiles, p)
+			continue
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		p, err := score.LoadProfile(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// estimatorNames lists the values accepted by -keysize
+const estimatorNames = "hamming, allpairs, kasiski, ic, autocorrelation, combined"
+
+// newEstimator returns the key size estimator name
+func newEstimator(name string) (xor.KeySizeEstimator, error) {
+	switch name {
+	case "hamming":
+		return xor.RankKeySizes, nil
+	case "allpairs":
+		return xor.RankKeySizesAllPairs, nil
+	case "kasiski":
+		return xor.RankKeySizesKasiski, nil
+	case "ic":
+		return xor.RankKeySizesIC, nil
+	case "autocorrelation":
+		return xor.RankKeySizesAutocorrelation, nil
+	case "combined":
+		return func(text []byte, minSize, maxSize int) []xor.KeySize {
+			return xor.RankKeySizesCombined(text, minSize, maxSize)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown key size estimator %q (want one of %s)", name, estimatorNames)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/scan"
+	"github.com/drio/cryptopals/xor"
+)
+
+// printKeySizes prints the candidate key sizes, most likely first, with
+// the score estimate gave them (the combined ranking when nil), and
+// returns them
+func printKeySizes(w io.Writer, text []byte, minSize, maxSize int, estimate xor.KeySizeEstimator) []xor.KeySize {
+	if estimate == nil {
+		estimate = func(text []byte, minSize, maxSize int) []xor.KeySize {
+			return xor.RankKeySizesCombined(text, minSize, maxSize)
+		}
+	}
+	ranked := estimate(text, minSize, maxSize)
+	for _, ks := range ranked {
+		fmt.Fprintf(w, "%2d  %.4f\n", ks.Size, ks.Score)
+	}
+	return ranked
+}
+
+// printAlternatives lists the runner-up keys of r when its winner is
+// ambiguous. r only holds as many candidates as -alts asked for.
+func printAlternatives(w io.Writer, label string, r xor.SingleByteResult) {
+	if len(r.Candidates) < 2 || !r.Ambiguous() {
+		return
+	}
+	fmt.Fprintf(w, "%sambiguous (confidence %.3f):\n", label, r.Confidence())
+	for _, c := range r.Candidates {
+		fmt.Fprintf(w, "  %#02x  %8.4f  %q\n", c.Key, c.Score, truncate(string(c.PlainText), 40))
+	}
+}
+
+func runSet1Ch3(cfg *config) (string, error) {
+	hexCipherText := `1b37373331363f78151b7f2b783431333d78397828372d363c78373e783a393b3736`
+	cipherTextBytes := codec.MustHexToBytes(hexCipherText)
+	r := xor.BreakSingleByteTop(cipherTextBytes, cfg.scorer, cfg.alts+1)
+	best := r.Best()
+	fmt.Fprintf(cfg.out, "%2.2f %s\n", best.Score, best.PlainText)
+	printAlternatives(cfg.out, "", r)
+	return string(best.PlainText), nil
+}
+
+func runSet1Ch4(cfg *config) (string, error) {
+	opts := scan.Options{
+		Workers:   cfg.workers,
+		Encoding:  scan.Hex,
+		Detectors: []scan.Detector{scan.SingleByteXOR{Scorer: cfg.scorer}},
+	}
+	top, err := scanTop(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	r := xor.BreakSingleByteTop(top.Input, cfg.scorer, cfg.alts+1)
+	fmt.Fprintf(cfg.out, "%s", r.Best().PlainText)
+	printAlternatives(cfg.out, "", r)
+	return string(r.Best().PlainText), nil
+}
+
+// scanTop scans the first input file of the challenge with opts and
+// returns the best finding
+func scanTop(cfg *config, opts scan.Options) (scan.Finding, error) {
+	f, err := cfg.open(0)
+	if err != nil {
+		return scan.Finding{}, err
+	}
+	defer f.Close()
+
+	top, err := scan.Top(context.Background(), f, opts, 1)
+	if err != nil {
+		return scan.Finding{}, fmt.Errorf("%s: %w", cfg.inputName(0), err)
+	}
+	if len(top) == 0 {
+		return scan.Finding{}, fmt.Errorf("%s: nothing found", cfg.inputName(0))
+	}
+	return top[0], nil
+}
+
+// readBase64Input reads and decodes the base64 of the first input file
+func readBase64Input(cfg *config) ([]byte, error) {
+	content, err := cfg.readInput(0)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := codec.Base64ToBytes(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.inputName(0), err)
+	}
+	return decoded, nil
+}
+
+func runSet1Ch5(cfg *config) (string, error) {
+	stanza := `Burning 'em, if you ain't quick and nimble
+I go crazy when I hear a cymbal`
+
+	cipherHex, err := xor.RepeatHex(stanza, "ICE")
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(cfg.out, cipherHex)
+	return cipherHex, nil
+}
+
+func runSet1Ch6(cfg *config) (string, error) {
+	cipherBytes, err := readBase64Input(cfg)
+	if err != nil {
+		return "", err
+	}
+	// Set1-Part 1: find key size
+	// keysize is 29 for the challenge
+	if cfg.part == 1 {
+		ranked := printKeySizes(cfg.out, cipherBytes, 2, 40, cfg.estimator)
+		if len(ranked) == 0 {
+			return "", fmt.Errorf("%s: too short to rank key sizes", cfg.inputName(0))
+		}
+		return strconv.Itoa(ranked[0].Size), nil
+	}
+
+	if cfg.magic {
+		result, err := xor.BreakKnownPlaintext(cipherBytes, xor.DefaultKnownPlaintextOptions)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(cfg.out, "type: %s\nkey: %x\n", result.FileType, result.Key)
+		return hex.EncodeToString(result.Key), nil
+	}
+
+	opts := xor.DefaultBreakOptions
+	opts.Scorer = cfg.scorer
+	opts.Estimator = cfg.estimator
+	opts.Profiles = cfg.profiles
+	result, err := xor.BreakRepeatingKeyXORWith(cipherBytes, opts)
+	if err != nil {
+		return "", err
+	}
+	if result.Language != "" {
+		fmt.Fprintf(cfg.out, "language: %s\n", result.Language)
+	}
+	fmt.Fprintf(cfg.out, "%s\n", result.PlainText)
+
+	if cfg.alts > 0 {
+		// rate the columns like the break did
+		s := cfg.scorer
+		for _, p := range cfg.profiles {
+			if p.Name == result.Language {
+				s = p.Bytes()
+			}
+		}
+		columns, err := xor.FindKeyCandidates(cipherBytes, len(result.Key), s, cfg.alts+1)
+		if err != nil {
+			return "", err
+		}
+		for i, c := range columns {
+			printAlternatives(cfg.out, fmt.Sprintf("key[%d] ", i), c)
+		}
+	}
+	return string(result.PlainText), nil
+}
+
+// AES-128 (cipher)
+// Mode in which we use AES-128: ECB (Electronic Codebook)
+// In ECB mode:
+// 1. plaintext is divided into keysize blocks.
+// 2. Each block is encrypted independently using the same key.
+func runSet1Ch7(cfg *config) (string, error) {
+	cipherText, err := readBase64Input(cfg)
+	if err != nil {
+		return "", err
+	}
+	key := []byte("YELLOW SUBMARINE")
+
+	plainText, err := modes.DecryptECB(cipherText, key)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "%s\n", plainText)
+	return string(plainText), nil
+}
+
+func runSet1Ch8(cfg *config) (string, error) {
+	opts := scan.Options{
+		Workers:   cfg.workers,
+		Encoding:  scan.Hex,
+		Detectors: []scan.Detector{scan.ECBRepetition{BlockSize: 16}},
+	}
+	top, err := scanTop(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(cfg.out, "%s\n%s\n", codec.ToHex(top.Input), top.Detail)
+	return codec.ToHex(top.Input), nil
+}
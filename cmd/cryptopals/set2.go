@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"slices"
+
+	"github.com/drio/cryptopals/attack"
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/oracle"
+	"github.com/drio/cryptopals/padding"
+)
+
+func runSet2Ch09(cfg *config) (string, error) {
+	i := "YELLOW SUBMARINE"
+	bi := []byte(i)
+	output := string(padding.MustPadPKCS7(bi, 20))
+	fmt.Fprintf(cfg.out, "input  %x\noutput %x\n", i, output)
+	return output, nil
+}
+
+func runSet2Ch10(cfg *config) (string, error) {
+	plainText := []byte(`This is a very important secret and should never shared with anyone`)
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, 16)
+
+	cipherText, err := modes.EncryptCBC(plainText, key, iv)
+	if err != nil {
+		return "", err
+	}
+	result, err := modes.DecryptCBC(cipherText, key, iv)
+	if err != nil {
+		return "", err
+	}
+	unpadResult, err := padding.ValidatePKCS7(result, modes.BlockSize)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "inputText : %s\n", plainText)
+	fmt.Fprintf(cfg.out, "cipherText: %x\n", cipherText)
+	fmt.Fprintf(cfg.out, "result    : %s\n", unpadResult)
+	return string(unpadResult), nil
+}
+
+func runSet2Ch11(cfg *config) (string, error) {
+	plainText := bytes.Repeat([]byte("A"), 128)
+
+	// Part 1: we have the oracle implemented
+	var cipherText []byte
+	var mode string
+	if cfg.seed != 0 {
+		cipherText, mode = oracle.AESFrom(rand.New(rand.NewSource(cfg.seed)), plainText)
+	} else {
+		cipherText, mode = oracle.AES(plainText)
+	}
+
+	// Part 2: write logic to determine if the oracle used ECB or CBC
+	call := attack.DetectMode(cipherText)
+	// test with: for i in $(seq 1 100); do cryptopals run set2 11 || break; done
+	if mode != call {
+		return "", fmt.Errorf("call did not match the oracle! oracle=%s call=%s plaintext=%x", mode, call, plainText)
+	}
+	fmt.Fprintf(cfg.out, "oracle=%s call=%s\n", mode, call)
+	return call, nil
+}
+
+func runSet2Ch12(cfg *config) (string, error) {
+	base64Plain := `Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK`
+	key := `YELLOW SUBMARINE`
+
+	encrypt, err := oracle.NewECBSuffix(key, base64Plain)
+	if err != nil {
+		return "", err
+	}
+
+	// The attack works out the block size (16), confirms the oracle runs
+	// in ECB mode and then breaks the suffix a byte at a time
+	r, err := attack.BreakECB(attack.OracleFunc(encrypt))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "block size: %d\n", r.BlockSize)
+	fmt.Fprintf(cfg.out, "AES cipher in %s mode. Good.\n", r.Mode)
+	fmt.Fprintf(cfg.out, "prefix: %d bytes, suffix: %d bytes, %d queries\n", r.PrefixLen, r.SuffixLen, r.Queries)
+	fmt.Fprintf(cfg.out, "%s\n", r.Suffix)
+	return string(r.Suffix), nil
+}
+
+func runSet2Ch14(cfg *config) (string, error) {
+	base64Plain := `Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK`
+
+	var encrypt func([]byte) []byte
+	var err error
+	if cfg.seed != 0 {
+		encrypt, err = oracle.NewRandomPrefixECBFrom(rand.New(rand.NewSource(cfg.seed)), base64Plain)
+	} else {
+		encrypt, err = oracle.NewRandomPrefixECB(base64Plain)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The prefix changes nothing about the block size and mode. The attack
+	// finds how long it is, pushes our input to the start of a block, and
+	// then it's challenge 12 again
+	r, err := attack.BreakECB(attack.OracleFunc(encrypt))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "block size: %d\nprefix: %d bytes\n", r.BlockSize, r.PrefixLen)
+	fmt.Fprintf(cfg.out, "%s\n", r.Suffix)
+	return string(r.Suffix), nil
+}
+
+func runSet2Ch15(cfg *config) (string, error) {
+	inputs := []string{
+		"ICE ICE BABY\x04\x04\x04\x04",
+		"ICE ICE BABY\x05\x05\x05\x05",
+		"ICE ICE BABY\x01\x02\x03\x04",
+	}
+
+	var answer string
+	for i, in := range inputs {
+		unpadded, err := padding.ValidatePKCS7([]byte(in), modes.BlockSize)
+		if err != nil {
+			fmt.Fprintf(cfg.out, "%q: %v\n", in, err)
+			continue
+		}
+		fmt.Fprintf(cfg.out, "%q: %q\n", in, unpadded)
+		if i == 0 {
+			answer = string(unpadded)
+		}
+	}
+	return answer, nil
+}
+
+func runSet2Ch16(cfg *config) (string, error) {
+	var ud *oracle.UserData
+	if cfg.seed != 0 {
+		ud = oracle.NewUserDataFrom(rand.New(rand.NewSource(cfg.seed)))
+	} else {
+		ud = oracle.NewUserData()
+	}
+	encrypt := func(input []byte) []byte { return ud.Encrypt(string(input)) }
+	target := []byte(";admin=true;")
+
+	// Part 1: the service quotes what we send, so asking for it fails
+	admin, err := ud.IsAdmin(encrypt(target))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "asking : admin=%t\n", admin)
+
+	// Part 2: flip the bits of the block before our input instead
+	forged, err := attack.CBCBitflip(encrypt, modes.BlockSize, target)
+	if err != nil {
+		return "", err
+	}
+	cookie, err := ud.Decrypt(forged)
+	if err != nil {
+		return "", err
+	}
+	if admin, err = ud.IsAdmin(forged); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "forged : %q\n", cookie)
+	fmt.Fprintf(cfg.out, "forged : admin=%t\n", admin)
+	return fmt.Sprint(admin), nil
+}
+
+func printBlocks(w io.Writer, plainText []byte) {
+	for i := 0; i < len(plainText); i += 16 {
+		end := min(i+16, len(plainText))
+		fmt.Fprintf(w, "Block %d: %x  // %q\n", i/16, plainText[i:end], plainText[i:end])
+	}
+}
+
+// printProfile prints the blocks of plainText and the profile in its
+// encryption, keys sorted, and returns the profile
+func printProfile(w io.Writer, pt *oracle.ProfileTool, plainText string) (map[string]string, error) {
+	cipherText := pt.Encrypt(plainText)
+	printBlocks(w, []byte(plainText))
+	fmt.Fprintf(w, "Decrypted profile: len=%d rem=%d\n", len(plainText), len(plainText)%16)
+	profile, err := pt.Decrypt(cipherText)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range slices.Sorted(maps.Keys(profile)) {
+		fmt.Fprintf(w, "  %s: %s\n", k, profile[k])
+	}
+	return profile, nil
+}
+
+func runSet2Ch13(cfg *config) (string, error) {
+	// Ch13-part3
+	// email=bar@foo.es&uid=10&role=user
+	pt := oracle.NewProfileTool()
+
+	// Generate block where admin is at the start of a block
+	if _, err := printProfile(cfg.out, pt, oracle.ProfileFor("foo@ba.comadmin")); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "\n")
+
+	// Generate block where &role= ends at the end of a block
+	if _, err := printProfile(cfg.out, pt, oracle.ProfileFor("foo@bar______")); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(cfg.out, "\n")
+
+	// Now,
+	//
+	// Generate a new cipherText that is:
+	// Block 0 from second profile "email=foo@bar___"
+	// Block 1 from second profile "___&uid=10&role="
+	// Block 2 from first profile  "admin&uid=10&rol"
+	//
+	// Which gives us:
+	// "email=foo@bar______&uid=10&role=admin&uid=10&rol"
+	// Let's try it:
+	profile, err := printProfile(cfg.out, pt, "email=foo@bar______&uid=10&role=admin&uid=10&rol")
+	if err != nil {
+		return "", err
+	}
+	return profile["role"], nil
+}
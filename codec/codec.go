@@ -0,0 +1,78 @@
+// Package codec holds the encoding helpers (hex, base64) used across the
+// challenges.
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// HexToBase64 encodes a hex string into base64
+func HexToBase64(data string) (string, error) {
+	decoded, err := HexToBytes(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(decoded), nil
+}
+
+// HexToBytes decodes a hex string into bytes. Whitespace between the
+// digits is ignored.
+func HexToBytes(data string) ([]byte, error) {
+	if decoded, err := hex.DecodeString(data); err == nil {
+		return decoded, nil
+	}
+	return Decode(Hex, data) // wrapped, or a *DecodeError with the offset
+}
+
+// MustHexToBytes is like HexToBytes but panics on error
+func MustHexToBytes(data string) []byte {
+	decoded, err := HexToBytes(data)
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+// Base64ToBytes decodes a base64 string into bytes. The standard and URL
+// safe alphabets are accepted, padded or not, and whitespace is ignored.
+func Base64ToBytes(a string) ([]byte, error) {
+	if decoded, err := base64.StdEncoding.DecodeString(a); err == nil {
+		return decoded, nil
+	}
+	return Decode(base64Of([]byte(a)), a)
+}
+
+// base64Of returns the base64 variant b is written in: standard and
+// padded unless its alphabet or its length say otherwise
+func base64Of(b []byte) Encoding {
+	compact := stripSpace(b)
+	body, padded := trimPadding(compact)
+	url := !all(body, isBase64Std) && all(body, isBase64URL)
+	raw := !padded && len(compact)%4 != 0
+	switch {
+	case url && raw:
+		return Base64RawURL
+	case url:
+		return Base64URL
+	case raw:
+		return Base64Raw
+	}
+	return Base64
+}
+
+// MustBase64ToBytes is like Base64ToBytes but panics on error
+func MustBase64ToBytes(a string) []byte {
+	decoded, err := Base64ToBytes(a)
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+// ToHex returns the hex representation of sBytes
+func ToHex(sBytes []byte) string {
+	return fmt.Sprintf("%02x", sBytes)
+}
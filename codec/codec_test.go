@@ -0,0 +1,69 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/drio/cryptopals/data"
+)
+
+func TestSet1Challenge01(t *testing.T) {
+	t.Run("Convert Hex to Base64", func(t *testing.T) {
+		input := `49276d206b696c6c696e6720796f757220627261696e206c696b65206120706f69736f6e6f7573206d757368726f6f6d`
+		expect := `SSdtIGtpbGxpbmcgeW91ciBicmFpbiBsaWtlIGEgcG9pc29ub3VzIG11c2hyb29t`
+
+		got, err := HexToBase64(input)
+		if err != nil || got != expect {
+			t.Errorf("wrong hex to base64 for %s\ngot %s\nexpected: %s", input, got, expect)
+		}
+	})
+
+	t.Run("HexToBase64 Alternative Test", func(t *testing.T) {
+		i := "49276d206b696c6c696e6720796f757220627261696e206c696b65206120706f69736f6e6f7573206d757368726f6f6d"
+		result, err := HexToBase64(i)
+		expected := "SSdtIGtpbGxpbmcgeW91ciBicmFpbiBsaWtlIGEgcG9pc29ub3VzIG11c2hyb29t"
+		if err != nil || result != expected {
+			t.Errorf("%s expected %s", result, expected)
+		}
+	})
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Run("Bad hex", func(t *testing.T) {
+		if _, err := HexToBase64("zz"); err == nil {
+			t.Errorf("expected an error for invalid hex")
+		}
+	})
+
+	t.Run("Bad base64", func(t *testing.T) {
+		if _, err := Base64ToBytes("!!!!"); err == nil {
+			t.Errorf("expected an error for invalid base64")
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadBase64File("does/not/exist.txt")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected os.ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestNewBase64Reader(t *testing.T) {
+	f, err := data.Open("set1/6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	streamed, err := io.ReadAll(NewBase64Reader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(streamed, MustBase64ToBytes(string(data.MustReadFile("set1/6")))) {
+		t.Errorf("streamed and loaded contents differ")
+	}
+}
@@ -0,0 +1,266 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// decodeChunk is how much a decoder reads from its source at once
+const decodeChunk = 4096
+
+// decoder decodes a stream, skipping whitespace and remembering where in
+// the stream every byte it holds came from, for the errors
+type decoder struct {
+	enc  Encoding
+	r    io.Reader
+	in   []byte  // input not decoded yet, without the whitespace
+	offs []int64 // offset in the stream of every byte of in
+	pos  int64   // offset in the stream of the next byte read
+	out  []byte  // decoded, not read yet
+	err  error
+
+	started bool // ascii85: the <~ frame check is done
+	framed  bool // ascii85: the input started with <~
+	done    bool // padding, or the end of the ascii85 frame, was seen
+	buf     [decodeChunk]byte
+}
+
+// NewDecoder returns a reader that decodes with enc what it reads from
+// r. Whitespace is skipped, except for quoted-printable. Bad input fails
+// with a *DecodeError holding its offset in the stream.
+func NewDecoder(enc Encoding, r io.Reader) io.Reader {
+	return &decoder{enc: enc, r: r}
+}
+
+func (d *decoder) Read(p []byte) (int, error) {
+	for len(d.out) == 0 && d.err == nil {
+		n, err := d.r.Read(d.buf[:])
+		for i, c := range d.buf[:n] {
+			if d.enc == QuotedPrintable || !isSpace(c) {
+				d.in = append(d.in, c)
+				d.offs = append(d.offs, d.pos+int64(i))
+			}
+		}
+		d.pos += int64(n)
+
+		flush := errors.Is(err, io.EOF)
+		if err != nil && !flush {
+			d.err = err
+		}
+		if derr := d.step(flush); derr != nil {
+			d.err = derr
+		} else if flush {
+			d.err = io.EOF
+		}
+	}
+
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	if n > 0 {
+		return n, nil
+	}
+	return 0, d.err
+}
+
+// step decodes as much of d.in as it can. flush means no more input is
+// coming.
+func (d *decoder) step(flush bool) error {
+	if len(d.in) == 0 && !flush {
+		return nil
+	}
+	if d.done {
+		if len(d.in) > 0 {
+			return d.errorAt(0, "data after the end")
+		}
+		return nil
+	}
+
+	var (
+		consumed int
+		bad      = -1
+		reason   string
+	)
+	switch d.enc {
+	case Hex:
+		consumed, bad, reason = d.hex(flush)
+	case Base64, Base64URL, Base64Raw, Base64RawURL, Base32:
+		consumed, bad, reason = d.blocks(flush)
+	case Ascii85:
+		consumed, bad, reason = d.ascii85(flush)
+	case QuotedPrintable:
+		consumed, bad, reason = d.quotedPrintable(flush)
+	}
+	if bad >= 0 {
+		return d.errorAt(bad, reason)
+	}
+
+	d.in = d.in[:copy(d.in, d.in[consumed:])]
+	d.offs = d.offs[:copy(d.offs, d.offs[consumed:])]
+	return nil
+}
+
+// errorAt reports the byte i of d.in, or the end of the stream when i is
+// past it
+func (d *decoder) errorAt(i int, reason string) error {
+	offset := d.pos
+	if i < len(d.offs) {
+		offset = d.offs[i]
+	}
+	return &DecodeError{d.enc, offset, reason}
+}
+
+func (d *decoder) hex(flush bool) (int, int, string) {
+	m := len(d.in) &^ 1
+	for i, c := range d.in[:m] {
+		if !isHexDigit(c) {
+			return 0, i, "illegal character"
+		}
+	}
+	if flush && m < len(d.in) {
+		if !isHexDigit(d.in[m]) {
+			return 0, m, "illegal character"
+		}
+		return 0, len(d.in), "odd number of digits"
+	}
+	n, _ := hex.Decode(d.in[:m/2], d.in[:m]) // in place, the digits are valid
+	d.out = append(d.out, d.in[:n]...)
+	return m, -1, ""
+}
+
+// blocks decodes the base64 and base32 encodings, whole blocks at a time
+func (d *decoder) blocks(flush bool) (int, int, string) {
+	var dec interface {
+		Decode(dst, src []byte) (int, error)
+		DecodedLen(n int) int
+	}
+	quantum, padded := 4, true
+	switch d.enc {
+	case Base64:
+		dec = base64.StdEncoding
+	case Base64URL:
+		dec = base64.URLEncoding
+	case Base64Raw:
+		dec, padded = base64.RawStdEncoding, false
+	case Base64RawURL:
+		dec, padded = base64.RawURLEncoding, false
+	case Base32:
+		dec, quantum = base32.StdEncoding, 8
+	}
+
+	m := len(d.in) / quantum * quantum
+	if flush && m < len(d.in) {
+		if padded {
+			return 0, len(d.in), "truncated input"
+		}
+		m = len(d.in) // unpadded, the last block is short
+	}
+	dst := make([]byte, dec.DecodedLen(m)+quantum)
+	n, err := dec.Decode(dst, d.in[:m])
+	if err != nil {
+		return 0, corruptOffset(err), "illegal character"
+	}
+	d.out = append(d.out, dst[:n]...)
+	if padded && m > 0 && d.in[m-1] == '=' {
+		d.done = true
+	}
+	return m, -1, ""
+}
+
+// corruptOffset returns the offset of a base64, base32 or ascii85
+// CorruptInputError
+func corruptOffset(err error) int {
+	var b64 base64.CorruptInputError
+	var b32 base32.CorruptInputError
+	var a85 ascii85.CorruptInputError
+	switch {
+	case errors.As(err, &b64):
+		return int(b64)
+	case errors.As(err, &b32):
+		return int(b32)
+	case errors.As(err, &a85):
+		return int(a85)
+	}
+	return 0
+}
+
+func (d *decoder) ascii85(flush bool) (int, int, string) {
+	skip := 0
+	if !d.started {
+		if len(d.in) < 2 && !flush {
+			return 0, -1, ""
+		}
+		d.started = true
+		if bytes.HasPrefix(d.in, []byte("<~")) {
+			d.framed, skip = true, 2
+		}
+	}
+
+	src, end := d.in[skip:], false
+	if d.framed {
+		if i := bytes.Index(src, []byte("~>")); i >= 0 {
+			src, end = src[:i], true
+		} else if flush {
+			return 0, len(d.in), "missing ~>"
+		} else if len(src) > 0 && src[len(src)-1] == '~' {
+			src = src[:len(src)-1] // maybe the start of ~>
+		}
+	}
+
+	dst := make([]byte, 4*len(src)+4)
+	n, nsrc, err := ascii85.Decode(dst, src, flush || end)
+	if err != nil {
+		return 0, skip + corruptOffset(err), "illegal character"
+	}
+	d.out = append(d.out, dst[:n]...)
+	if end {
+		d.done = true
+		return skip + len(src) + 2, -1, ""
+	}
+	return skip + nsrc, -1, ""
+}
+
+// quotedPrintable decodes the complete lines of d.in
+func (d *decoder) quotedPrintable(flush bool) (int, int, string) {
+	m := bytes.LastIndexByte(d.in, '\n') + 1
+	if flush {
+		m = len(d.in)
+	}
+
+	for start := 0; start < m; {
+		line := d.in[start:m]
+		next := m
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line, next = line[:i+1], start+i+1
+		}
+
+		body := bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		eol := line[len(body):]
+		body = bytes.TrimRight(body, " \t")
+		if bytes.HasSuffix(body, []byte("=")) {
+			body, eol = body[:len(body)-1], nil // soft line break
+		}
+
+		for i := 0; i < len(body); i++ {
+			c := body[i]
+			if c != '=' {
+				d.out = append(d.out, c)
+				continue
+			}
+			if i+2 >= len(body) || !isHexDigit(body[i+1]) || !isHexDigit(body[i+2]) {
+				return 0, start + i, "bad escape"
+			}
+			var b [1]byte
+			hex.Decode(b[:], body[i+1:i+3])
+			d.out = append(d.out, b[0])
+			i += 2
+		}
+		d.out = append(d.out, eol...)
+		start = next
+	}
+	return m, -1, ""
+}
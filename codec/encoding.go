@@ -0,0 +1,234 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// Encoding is a binary-to-text encoding
+type Encoding int
+
+const (
+	Hex             Encoding = iota // hex digits, either case
+	Base64                          // standard base64, padded
+	Base64URL                       // URL safe base64, padded
+	Base64Raw                       // standard base64, unpadded
+	Base64RawURL                    // URL safe base64, unpadded
+	Base32                          // standard base32, padded
+	Ascii85                         // btoa/Adobe ascii85, with or without the <~ ~> frame
+	QuotedPrintable                 // MIME quoted-printable
+)
+
+var encodingNames = [...]string{"hex", "base64", "base64url", "base64raw", "base64rawurl", "base32", "ascii85", "qp"}
+
+func (e Encoding) String() string {
+	if e < 0 || int(e) >= len(encodingNames) {
+		return "invalid"
+	}
+	return encodingNames[e]
+}
+
+var (
+	// ErrCorrupt is wrapped by the DecodeErrors
+	ErrCorrupt = errors.New("codec: corrupt input")
+	// ErrUnknownEncoding is returned when the encoding can't be told
+	ErrUnknownEncoding = errors.New("codec: unknown encoding")
+)
+
+// DecodeError reports a bad character, or a truncated input, at Offset
+// bytes from the start of the input
+type DecodeError struct {
+	Encoding Encoding
+	Offset   int64
+	Reason   string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("codec: decoding %s: %s at offset %d", e.Encoding, e.Reason, e.Offset)
+}
+
+func (e *DecodeError) Unwrap() error { return ErrCorrupt }
+
+// ParseEncoding returns the encoding called name (see Encoding.String)
+func ParseEncoding(name string) (Encoding, error) {
+	for i, n := range encodingNames {
+		if n == name {
+			return Encoding(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q (want one of %s)", ErrUnknownEncoding, name, strings.Join(encodingNames[:], ", "))
+}
+
+// Encode returns data encoded with enc
+func Encode(enc Encoding, data []byte) string {
+	var buf bytes.Buffer
+	w := NewEncoder(enc, &buf)
+	w.Write(data) // a bytes.Buffer doesn't fail
+	w.Close()
+	return buf.String()
+}
+
+// Decode decodes s with enc. Whitespace between the characters is
+// ignored, except for quoted-printable where line breaks mean something.
+func Decode(enc Encoding, s string) ([]byte, error) {
+	return io.ReadAll(NewDecoder(enc, strings.NewReader(s)))
+}
+
+// DecodeAuto detects the encoding of s and decodes it
+func DecodeAuto(s string) ([]byte, Encoding, error) {
+	enc, err := Detect([]byte(s))
+	if err != nil {
+		return nil, enc, err
+	}
+	decoded, err := Decode(enc, s)
+	return decoded, enc, err
+}
+
+// NewEncoder returns a writer that encodes with enc what is written to it
+// into w. Close flushes the last partial block; it doesn't close w.
+func NewEncoder(enc Encoding, w io.Writer) io.WriteCloser {
+	switch enc {
+	case Hex:
+		return nopCloser{hex.NewEncoder(w)}
+	case Base64URL:
+		return base64.NewEncoder(base64.URLEncoding, w)
+	case Base64Raw:
+		return base64.NewEncoder(base64.RawStdEncoding, w)
+	case Base64RawURL:
+		return base64.NewEncoder(base64.RawURLEncoding, w)
+	case Base32:
+		return base32.NewEncoder(base32.StdEncoding, w)
+	case Ascii85:
+		return ascii85.NewEncoder(w)
+	case QuotedPrintable:
+		return quotedprintable.NewWriter(w)
+	}
+	return base64.NewEncoder(base64.StdEncoding, w)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// base32Unpadded is how long unpadded base32 must be to be told from
+// base64: that many base64 characters would all fall in the base32
+// alphabet with a chance of 2^-32
+const base32Unpadded = 32
+
+// Detect guesses the encoding of data. When several fit it prefers the
+// smallest alphabet: hex digits are base32 and base64 too.
+func Detect(data []byte) (Encoding, error) {
+	compact := stripSpace(data)
+	switch {
+	case len(compact) == 0:
+		return 0, fmt.Errorf("%w: empty input", ErrUnknownEncoding)
+	case bytes.HasPrefix(compact, []byte("<~")):
+		return Ascii85, nil
+	case len(compact)%2 == 0 && all(compact, isHexDigit):
+		return Hex, nil
+	}
+
+	body, padded := trimPadding(compact)
+	switch {
+	case all(body, isBase32) && len(compact)%8 == 0 && (padded || len(compact) >= base32Unpadded):
+		return Base32, nil
+	case all(body, isBase64Std):
+		return base64Variant(Base64, Base64Raw, compact, padded)
+	case all(body, isBase64URL):
+		return base64Variant(Base64URL, Base64RawURL, compact, padded)
+	case isQuotedPrintable(data):
+		return QuotedPrintable, nil
+	case all(compact, isAscii85):
+		return Ascii85, nil
+	}
+	return 0, ErrUnknownEncoding
+}
+
+// base64Variant picks the padded or raw variant of base64 for compact
+func base64Variant(padded, raw Encoding, compact []byte, hasPadding bool) (Encoding, error) {
+	switch {
+	case len(compact)%4 == 0:
+		return padded, nil
+	case !hasPadding && len(compact)%4 != 1:
+		return raw, nil
+	}
+	return 0, fmt.Errorf("%w: base64 of a bad length", ErrUnknownEncoding)
+}
+
+// trimPadding removes up to 6 trailing '=' (base32 uses that many)
+func trimPadding(b []byte) ([]byte, bool) {
+	n := len(b)
+	for n > 0 && len(b)-n < 6 && b[n-1] == '=' {
+		n--
+	}
+	return b[:n], n < len(b)
+}
+
+// isQuotedPrintable reports whether data is printable ASCII using at least
+// one quoted-printable escape, all of them well formed
+func isQuotedPrintable(data []byte) bool {
+	escapes := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '=':
+			rest := data[i+1:]
+			switch {
+			case len(rest) >= 2 && isHexDigit(rest[0]) && isHexDigit(rest[1]):
+				i += 2
+			case bytes.HasPrefix(rest, []byte("\r\n")), bytes.HasPrefix(rest, []byte("\n")):
+			default:
+				return false
+			}
+			escapes++
+		case c == '\n' || c == '\r' || c == '\t':
+		case c < 0x20 || c > 0x7e:
+			return false
+		}
+	}
+	return escapes > 0
+}
+
+func all(b []byte, ok func(byte) bool) bool {
+	for _, c := range b {
+		if !ok(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isAlnum(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9'
+}
+
+func isBase32(c byte) bool    { return 'A' <= c && c <= 'Z' || '2' <= c && c <= '7' }
+func isBase64Std(c byte) bool { return isAlnum(c) || c == '+' || c == '/' }
+func isBase64URL(c byte) bool { return isAlnum(c) || c == '-' || c == '_' }
+func isAscii85(c byte) bool   { return '!' <= c && c <= 'u' || c == 'z' }
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func stripSpace(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		if !isSpace(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var encodings = []Encoding{Hex, Base64, Base64URL, Base64Raw, Base64RawURL, Base32, Ascii85, QuotedPrintable}
+
+func TestEncoding(t *testing.T) {
+	inputs := [][]byte{
+		nil,
+		[]byte("f"),
+		[]byte("Cooking MC's like a pound of bacon"),
+		[]byte("caf\xc3\xa9 = 100%\r\nand a long line " + strings.Repeat("-", 100)),
+		{0, 0, 0, 0, 0xff, 0xfe, 0xfb, 0x3e, 0x3f},
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		for _, enc := range encodings {
+			for _, in := range inputs {
+				got, err := Decode(enc, Encode(enc, in))
+				if err != nil || !bytes.Equal(got, in) {
+					t.Errorf("%s: %q round tripped to %q, %v", enc, in, got, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Whitespace", func(t *testing.T) {
+		in := inputs[2]
+		for _, enc := range encodings {
+			if enc == QuotedPrintable {
+				continue
+			}
+			s := Encode(enc, in)
+			var wrapped strings.Builder
+			for i := 0; i < len(s); i += 7 {
+				wrapped.WriteString(s[i:min(i+7, len(s))])
+				wrapped.WriteString(" \t\r\n")
+			}
+			got, err := Decode(enc, wrapped.String())
+			if err != nil || !bytes.Equal(got, in) {
+				t.Errorf("%s: got %q, %v", enc, got, err)
+			}
+		}
+	})
+
+	t.Run("Streaming", func(t *testing.T) {
+		in := bytes.Repeat(inputs[3], 200)
+		for _, enc := range encodings {
+			var buf bytes.Buffer
+			w := NewEncoder(enc, &buf)
+			for _, c := range in {
+				w.Write([]byte{c})
+			}
+			w.Close()
+
+			got, err := io.ReadAll(NewDecoder(enc, iotest.OneByteReader(&buf)))
+			if err != nil || !bytes.Equal(got, in) {
+				t.Errorf("%s: streamed decoding differs, %v", enc, err)
+			}
+		}
+	})
+
+	t.Run("Error offsets", func(t *testing.T) {
+		cases := []struct {
+			enc    Encoding
+			in     string
+			offset int64
+		}{
+			{Hex, "00 11\n2x", 7},
+			{Hex, "abc", 3},
+			{Base64, "SGVs\nbG8!", 8},
+			{Base64, "SGVsbG8=SGVs", 8},
+			{Base64, "SGVsbG8", 7},
+			{Base64Raw, "SGVs=", 4},
+			{Base64URL, "ab+/", 2},
+			{Base32, "NBSWY3DP\r\nEB3W1===", 14},
+			{Ascii85, "<~87cURD]i,\"Ebo80~", 18},
+			{Ascii85, "87cUR{", 5},
+			{QuotedPrintable, "ok\nbad =G1 escape", 7},
+		}
+		for _, tc := range cases {
+			_, err := Decode(tc.enc, tc.in)
+			var de *DecodeError
+			if !errors.As(err, &de) || !errors.Is(err, ErrCorrupt) {
+				t.Errorf("%s %q: expected a DecodeError, got %v", tc.enc, tc.in, err)
+				continue
+			}
+			if de.Offset != tc.offset {
+				t.Errorf("%s %q: expected offset %d, got %d (%v)", tc.enc, tc.in, tc.offset, de.Offset, err)
+			}
+		}
+	})
+
+	t.Run("Detect", func(t *testing.T) {
+		cases := []struct {
+			in  string
+			enc Encoding
+		}{
+			{"49276d206b696c6c696e67\n", Hex},
+			{"SSdtIGtpbGxpbmcgeW91ciBicmFpbg==", Base64},
+			{"SSdtIGtpbGxpbmcgeW91ciBicmFpbg", Base64Raw},
+			{"-_-_AAAA", Base64URL},
+			{"-_-_AAA", Base64RawURL},
+			{"NBSWY3DP", Base64},
+			{"NBSWY3DPEE======", Base32},
+			{"JBSWY3DPEBLW64TMMQQGC3TEEBWW64TFEBZXIYLG", Base32},
+			{"<~87cURD]i,\"Ebo80~>", Ascii85},
+			{"87cURD]i,\"Ebo80", Ascii85},
+			{"caf=C3=A9 au lait=\r\n", QuotedPrintable},
+		}
+		for _, tc := range cases {
+			if enc, err := Detect([]byte(tc.in)); err != nil || enc != tc.enc {
+				t.Errorf("%q: expected %s, got %s, %v", tc.in, tc.enc, enc, err)
+			}
+		}
+		for _, in := range []string{"", "   ", "héllo\x00"} {
+			if _, err := Detect([]byte(in)); !errors.Is(err, ErrUnknownEncoding) {
+				t.Errorf("%q: expected ErrUnknownEncoding, got %v", in, err)
+			}
+		}
+	})
+
+	t.Run("DecodeAuto", func(t *testing.T) {
+		for _, enc := range encodings {
+			got, detected, err := DecodeAuto(Encode(enc, inputs[3]))
+			if err != nil || !bytes.Equal(got, inputs[3]) {
+				t.Errorf("%s detected as %s: got %q, %v", enc, detected, got, err)
+			}
+		}
+	})
+
+	t.Run("Ascii85 frame", func(t *testing.T) {
+		for in, want := range map[string]string{
+			"<~87cURDZ~>":            "Hello",
+			"<~87cURD]i,\"Ebo80~>\n": "Hello World!",
+			"<~\n87cUR\nDZ\n~>":      "Hello",
+			"<~~>":                   "",
+		} {
+			got, err := Decode(Ascii85, in)
+			if err != nil || string(got) != want {
+				t.Errorf("%q: got %q, %v", in, got, err)
+			}
+			got, err = io.ReadAll(NewDecoder(Ascii85, iotest.OneByteReader(strings.NewReader(in))))
+			if err != nil || string(got) != want {
+				t.Errorf("%q: streamed %q, %v", in, got, err)
+			}
+		}
+
+		got, detected, err := DecodeAuto("<~87cURDZ~>")
+		if err != nil || detected != Ascii85 || string(got) != "Hello" {
+			t.Errorf("DecodeAuto: got %q as %s, %v", got, detected, err)
+		}
+		if _, err := Decode(Ascii85, "<~87cURDZ~>87"); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("expected ErrCorrupt for data after ~>, got %v", err)
+		}
+	})
+
+	t.Run("ParseEncoding", func(t *testing.T) {
+		for _, enc := range encodings {
+			if got, err := ParseEncoding(enc.String()); err != nil || got != enc {
+				t.Errorf("%s: got %s, %v", enc, got, err)
+			}
+		}
+		if _, err := ParseEncoding("rot13"); !errors.Is(err, ErrUnknownEncoding) {
+			t.Errorf("expected ErrUnknownEncoding, got %v", err)
+		}
+	})
+
+	t.Run("Base64 variants", func(t *testing.T) {
+		want := []byte{0xfb, 0xff, 0xbf, 0xfe}
+		for _, in := range []string{"+/+//g==", "-_-__g==", "+/+//g", "-_-__g", "+/+/\r\n/g==\n"} {
+			if got, err := Base64ToBytes(in); err != nil || !bytes.Equal(got, want) {
+				t.Errorf("%q: got %x, %v", in, got, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LoadBase64File reads fn and decodes its base64 content, however it is
+// wrapped
+func LoadBase64File(fn string) ([]byte, error) {
+	content, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("codec: %w", err)
+	}
+
+	data, err := Base64ToBytes(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn, err)
+	}
+	return data, nil
+}
+
+// NewBase64Reader decodes the (wrapped) standard base64 it reads from r
+// as it streams through, where LoadBase64File holds it all in memory
+func NewBase64Reader(r io.Reader) io.Reader {
+	return NewDecoder(Base64, r)
+}
+
+// MustLoadBase64File is like LoadBase64File but panics on error
+func MustLoadBase64File(fn string) []byte {
+	data, err := LoadBase64File(fn)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// ReadFile loads the full content of filePath in memory
+func ReadFile(filePath string) ([]byte, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("codec: %w", err)
+	}
+	return data, nil
+}
+
+// MustReadFile is like ReadFile but panics on error
+func MustReadFile(filePath string) []byte {
+	data, err := ReadFile(filePath)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// EachLine calls cb with every line of fn and its (1 based) line number.
+// It stops at the first error returned by cb.
+func EachLine(fn string, cb func(string, int) error) error {
+	file, err := os.Open(fn)
+	if err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if err := cb(scanner.Text(), lineNumber); err != nil {
+			return fmt.Errorf("codec: %s:%d: %w", fn, lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("codec: scanning %s: %w", fn, err)
+	}
+	return nil
+}
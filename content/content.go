@@ -0,0 +1,231 @@
+// Package content guesses what kind of data a byte slice holds, so the
+// breakers can tell a plausible decryption from noise.
+package content
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// Kind is a kind of content
+type Kind int
+
+const (
+	Unknown Kind = iota // binary data of no known format
+	English             // English prose
+	Text                // other printable UTF-8: other languages, code, logs
+	JSON                // a JSON document
+	XML                 // an XML document
+	Base64              // base64 encoded data
+	Binary              // a known binary file format, see Result.Format
+	Random              // compressed, encrypted or random bytes
+)
+
+var kindNames = [...]string{"unknown", "english", "text", "json", "xml", "base64", "binary", "random"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "invalid"
+	}
+	return kindNames[k]
+}
+
+// IsText reports whether k is printable text of some sort
+func (k Kind) IsText() bool {
+	return k >= English && k <= Base64
+}
+
+// Result is what Classify makes of some content
+type Result struct {
+	Kind       Kind
+	Confidence float64 // in [0, 1]
+	Format     string  // the file format, for Binary
+}
+
+// Plausible reports whether the content looks like something somebody
+// would encrypt: text or a known file format, not noise
+func (r Result) Plausible() bool {
+	return r.Kind.IsText() || r.Kind == Binary
+}
+
+// magic are the prefixes of the binary formats Classify recognises
+var magic = []struct {
+	format string
+	prefix string
+}{
+	{"png", "\x89PNG\r\n\x1a\n"},
+	{"jpeg", "\xff\xd8\xff"},
+	{"gif", "GIF8"},
+	{"pdf", "%PDF-"},
+	{"zip", "PK\x03\x04"},
+	{"gzip", "\x1f\x8b\x08"},
+	{"bzip2", "BZh"},
+	{"xz", "\xfd7zXZ\x00"},
+	{"7z", "7z\xbc\xaf\x27\x1c"},
+	{"elf", "\x7fELF"},
+}
+
+const (
+	// printableShare is the share of printable runes text needs
+	printableShare = 0.95
+	// randomEntropy is how close to the largest possible entropy random
+	// data gets
+	randomEntropy = 0.9
+	// minRandom is the shortest input Classify calls random: shorter
+	// inputs can't reach a high entropy
+	minRandom = 32
+)
+
+// Classify guesses the kind of content in b
+func Classify(b []byte) Result {
+	if len(b) == 0 {
+		return Result{Unknown, 0, ""}
+	}
+	for _, m := range magic {
+		if bytes.HasPrefix(b, []byte(m.prefix)) {
+			// the chance random bytes start with the magic
+			return Result{Binary, 1 - math.Pow(256, -float64(len(m.prefix))), m.format}
+		}
+	}
+
+	if share := printable(b); share >= printableShare {
+		return classifyText(b, share)
+	}
+
+	h := Entropy(b)
+	maxH := math.Log2(float64(min(len(b), 256)))
+	if len(b) >= minRandom && h >= randomEntropy*maxH {
+		return Result{Random, h / maxH, ""}
+	}
+	return Result{Unknown, 1 - h/8, ""}
+}
+
+// printable returns the share of b made of valid, printable UTF-8 runes
+// or whitespace
+func printable(b []byte) float64 {
+	good, n := 0, 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		n++
+		if r != utf8.RuneError && (unicode.IsPrint(r) || unicode.IsSpace(r)) {
+			good++
+		}
+	}
+	return float64(good) / float64(n)
+}
+
+// classifyText tells the kinds of text apart. share is the printable
+// share of b.
+func classifyText(b []byte, share float64) Result {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 {
+		return Result{Text, share, ""}
+	}
+
+	switch {
+	case (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed):
+		return Result{JSON, 1, ""}
+	case trimmed[0] == '<' && wellFormedXML(trimmed):
+		return Result{XML, 1, ""}
+	case isBase64(trimmed):
+		// short runs of letters are base64 too
+		return Result{Base64, min(1, float64(len(trimmed))/64), ""}
+	}
+
+	letters, spaces, n := 0, 0, 0
+	for _, r := range string(b) {
+		n++
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case r == ' ':
+			spaces++
+		}
+	}
+	prose := float64(letters+spaces) / float64(n)
+	if prose >= 0.75 && spaces > 0 && float64(spaces)/float64(n) < 0.35 {
+		if p, _ := score.Detect(b, languages); p != nil && p.Name == "english" {
+			return Result{English, prose, ""}
+		}
+	}
+	return Result{Text, share, ""}
+}
+
+// languages are the profiles English prose is told apart from
+var languages = func() []*score.Profile {
+	ps := []*score.Profile{}
+	for _, p := range score.Profiles() {
+		if p.Name != "code" {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}()
+
+// wellFormedXML reports whether b parses as XML with a root element
+func wellFormedXML(b []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	elements := 0
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return elements > 0
+		}
+		if err != nil {
+			return false
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			elements++
+		}
+	}
+}
+
+// isBase64 reports whether b is standard base64, possibly wrapped in lines
+func isBase64(b []byte) bool {
+	compact := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch {
+		case c == '\n' || c == '\r':
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '+', c == '/', c == '=':
+			compact = append(compact, c)
+		default:
+			return false
+		}
+	}
+	if len(compact) < 8 || len(compact)%4 != 0 {
+		return false
+	}
+	_, err := base64.StdEncoding.DecodeString(string(compact))
+	return err == nil
+}
+
+// Entropy returns the Shannon entropy of the bytes of b in bits per byte
+func Entropy(b []byte) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+	h := 0.0
+	n := float64(len(b))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,101 @@
+package content
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/drio/cryptopals/data"
+)
+
+func readFile(t *testing.T, fn string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestClassify(t *testing.T) {
+	lyrics := data.MustReadFile("set1/output6")
+
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(lyrics)
+	w.Close()
+
+	garbled := bytes.Clone(lyrics)
+	for i := range garbled {
+		if i%5 == 0 {
+			garbled[i] ^= 0xa7 // one byte of a 5 byte key is wrong
+		}
+	}
+
+	cases := []struct {
+		name  string
+		input []byte
+		kind  Kind
+	}{
+		{"English prose", lyrics, English},
+		{"Short English", []byte("Cooking MC's like a pound of bacon"), English},
+		{"Spanish", readFile(t, "../score/testdata/spanish.txt"), Text},
+		{"Go source", []byte("package main\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n"), Text},
+		{"JSON", []byte(`{"user": "alice", "roles": ["admin", "ops"], "id": 1234}`), JSON},
+		{"JSON array", []byte(" [1, 2, 3]\n"), JSON},
+		{"XML", []byte(`<?xml version="1.0"?><note><to>Bob</to><body>hi</body></note>`), XML},
+		{"Base64", data.MustReadFile("set1/6"), Base64},
+		{"gzip", gz.Bytes(), Binary},
+		{"Random", random, Random},
+		{"Wrong key byte", garbled, Unknown},
+		{"Empty", nil, Unknown},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Classify(tc.input)
+			if r.Kind != tc.kind {
+				t.Errorf("expected %s, got %s (%.2f)", tc.kind, r.Kind, r.Confidence)
+			}
+			if r.Confidence < 0 || r.Confidence > 1 {
+				t.Errorf("confidence out of range: %f", r.Confidence)
+			}
+			if r.Plausible() != (tc.kind != Random && tc.kind != Unknown) {
+				t.Errorf("wrong plausibility for %s", r.Kind)
+			}
+		})
+	}
+
+	if r := Classify(gz.Bytes()); r.Format != "gzip" {
+		t.Errorf("expected the gzip format, got %q", r.Format)
+	}
+}
+
+func TestIsBase64(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected bool
+	}{
+		{base64.StdEncoding.EncodeToString([]byte("YELLOW SUBMARINE")), true},
+		{"SGVsbG8g\nd29ybGQ=\n", true},
+		{"SGVsbG8", false},      // not a multiple of 4
+		{"SGVs bG8g", false},    // a space
+		{"SGVsbG8=SGVs", false}, // padding in the middle
+	}
+	for _, tc := range cases {
+		if got := isBase64([]byte(tc.input)); got != tc.expected {
+			t.Errorf("%q: expected %v", tc.input, tc.expected)
+		}
+	}
+}
+
+func TestKindString(t *testing.T) {
+	if English.String() != "english" || Kind(99).String() != "invalid" {
+		t.Errorf("wrong names %s, %s", English, Kind(99))
+	}
+}
@@ -0,0 +1,109 @@
+// Package data embeds the challenge data files, so the tests and the
+// runners find them whatever the working directory. A Dataset reads them
+// from any fs.FS, for custom corpora.
+package data
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//go:embed set1 set2
+var files embed.FS
+
+// Embedded returns the challenge data files built into the binary
+func Embedded() fs.FS {
+	return files
+}
+
+// Dataset reads named data files from a filesystem. A name is a slash
+// separated path, like "set1/6"; the .txt extension is optional.
+type Dataset struct {
+	fsys fs.FS
+}
+
+// New returns a dataset reading from fsys
+func New(fsys fs.FS) *Dataset {
+	return &Dataset{fsys}
+}
+
+// Default is the dataset of the package level functions: the embedded
+// files, unless a caller swaps it
+var Default = New(Embedded())
+
+// Open opens the data file called name
+func (d *Dataset) Open(name string) (fs.File, error) {
+	f, err := d.fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+		f, err = d.fsys.Open(name + ".txt")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	return f, nil
+}
+
+// ReadFile returns the content of the data file called name
+func (d *Dataset) ReadFile(name string) ([]byte, error) {
+	f, err := d.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("data: reading %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// Lines returns the lines of the data file called name, without their
+// line breaks (\n or \r\n)
+func (d *Dataset) Lines(name string) ([]string, error) {
+	content, err := d.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return []string{}, nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines, nil
+}
+
+// Open opens the data file called name in the Default dataset
+func Open(name string) (fs.File, error) {
+	return Default.Open(name)
+}
+
+// ReadFile returns the content of the data file called name in the
+// Default dataset
+func ReadFile(name string) ([]byte, error) {
+	return Default.ReadFile(name)
+}
+
+// MustReadFile is like ReadFile but panics on error
+func MustReadFile(name string) []byte {
+	content, err := ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return content
+}
+
+// Lines returns the lines of the data file called name in the Default
+// dataset
+func Lines(name string) ([]string, error) {
+	return Default.Lines(name)
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDataset(t *testing.T) {
+	t.Run("Embedded files", func(t *testing.T) {
+		for _, name := range []string{"set1/4", "set1/6", "set1/7", "set1/8", "set1/output6", "set2/10", "set1/6.txt"} {
+			content, err := ReadFile(name)
+			if err != nil || len(content) == 0 {
+				t.Errorf("%s: got %d bytes, %v", name, len(content), err)
+			}
+		}
+	})
+
+	t.Run("Lines", func(t *testing.T) {
+		lines, err := Lines("set1/4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 327 || len(lines[0]) != 60 {
+			t.Errorf("expected 327 lines of 60 hex digits, got %d (first %q)", len(lines), lines[0])
+		}
+	})
+
+	t.Run("Custom filesystem", func(t *testing.T) {
+		d := New(fstest.MapFS{
+			"corpus/a.txt": {Data: []byte("one\r\ntwo\n")},
+			"corpus/b.bin": {Data: []byte{0, 1}},
+			"empty.txt":    {Data: nil},
+		})
+		if lines, err := d.Lines("corpus/a"); err != nil || strings.Join(lines, "|") != "one|two" {
+			t.Errorf("expected [one two], got %q, %v", lines, err)
+		}
+		if content, err := d.ReadFile("corpus/b.bin"); err != nil || len(content) != 2 {
+			t.Errorf("expected 2 bytes, got %v, %v", content, err)
+		}
+		if lines, err := d.Lines("empty"); err != nil || len(lines) != 0 {
+			t.Errorf("expected no lines, got %q, %v", lines, err)
+		}
+		if _, err := d.Open("set1/4"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := ReadFile("set9/1"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+// Package modes implements the AES-128 block cipher modes of operation
+// (ECB and CBC) used in the challenges.
+package modes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/drio/cryptopals/padding"
+	"github.com/drio/cryptopals/xor"
+)
+
+// BlockSize is the AES block size in bytes
+const BlockSize = aes.BlockSize
+
+var (
+	// ErrBadKeySize is returned when the key is not 16 bytes (AES-128)
+	ErrBadKeySize = errors.New("modes: invalid key size")
+	// ErrBadIVSize is returned when the IV is not one block long
+	ErrBadIVSize = errors.New("modes: invalid IV size")
+	// ErrNotBlockAligned is returned when a ciphertext is not a multiple
+	// of the block size
+	ErrNotBlockAligned = errors.New("modes: input is not a multiple of the block size")
+)
+
+// NewAESCipher returns an AES-128 block for key
+func NewAESCipher(key []byte) (cipher.Block, error) {
+	if len(key) != 16 {
+		return nil, fmt.Errorf("%w: %d", ErrBadKeySize, len(key))
+	}
+
+	return aes.NewCipher(key)
+}
+
+// checkBlocks validates the inputs shared by the decrypt functions
+func checkBlocks(cipherText []byte) error {
+	if len(cipherText)%BlockSize != 0 {
+		return fmt.Errorf("%w: %d bytes", ErrNotBlockAligned, len(cipherText))
+	}
+	return nil
+}
+
+func checkIV(iv []byte) error {
+	if len(iv) != BlockSize {
+		return fmt.Errorf("%w: %d", ErrBadIVSize, len(iv))
+	}
+	return nil
+}
+
+// EncryptCBC encrypts plainText with AES-128 in CBC mode
+// pass innitializion vector
+//
+// Encrypt:
+// For each plaintext block Pᵢ:
+//
+//	Cᵢ = Encrypt(Pᵢ XOR Cᵢ₋₁)
+//
+// where C₋₁ is the IV for the first block
+func EncryptCBC(plainText, key, iv []byte) ([]byte, error) {
+	block, err := NewAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(iv); err != nil {
+		return nil, err
+	}
+
+	paddedPlainText := padding.MustPadPKCS7(plainText, BlockSize)
+	cipherText := make([]byte, len(paddedPlainText))
+	prevBlock := iv
+
+	for i := 0; i < len(paddedPlainText); i += BlockSize {
+		plainChunk := paddedPlainText[i : i+BlockSize]
+		xorBlock := xor.MustBytes(plainChunk, prevBlock)
+		block.Encrypt(cipherText[i:i+BlockSize], xorBlock)
+		prevBlock = cipherText[i : i+BlockSize]
+	}
+
+	return cipherText, nil
+}
+
+// MustEncryptCBC is like EncryptCBC but panics on error
+func MustEncryptCBC(plainText, key, iv []byte) []byte {
+	cipherText, err := EncryptCBC(plainText, key, iv)
+	if err != nil {
+		panic(err)
+	}
+	return cipherText
+}
+
+// DecryptCBC decrypts cipherText with AES-128 in CBC mode. The padding is
+// left in place.
+//
+// Decrypt:
+// For each ciphertext block Cᵢ:
+//
+//	Pᵢ = Decrypt(Cᵢ) XOR Cᵢ₋₁
+//
+// where C₋₁ is the IV for the first block
+func DecryptCBC(cipherText, key, iv []byte) ([]byte, error) {
+	block, err := NewAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(iv); err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(cipherText); err != nil {
+		return nil, err
+	}
+
+	plainText := make([]byte, len(cipherText))
+	prevBlock := iv
+	for i := 0; i < len(cipherText); i += BlockSize {
+		cipherChunk := cipherText[i : i+BlockSize]
+
+		// decrypt the ciphertext block
+		decrypted := make([]byte, BlockSize)
+		block.Decrypt(decrypted, cipherChunk)
+
+		// XOR with previous cipherText (iv on the first iteration)
+		xorBlock := xor.MustBytes(decrypted, prevBlock)
+
+		copy(plainText[i:i+BlockSize], xorBlock)
+
+		prevBlock = cipherChunk
+	}
+
+	return plainText, nil
+}
+
+// MustDecryptCBC is like DecryptCBC but panics on error
+func MustDecryptCBC(cipherText, key, iv []byte) []byte {
+	plainText, err := DecryptCBC(cipherText, key, iv)
+	if err != nil {
+		panic(err)
+	}
+	return plainText
+}
+
+// EncryptECB encrypts plainText with AES in ECB mode
+func EncryptECB(plainText, key []byte) ([]byte, error) {
+	block, err := NewAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	paddedPlainText := padding.MustPadPKCS7(plainText, BlockSize)
+	cipherText := make([]byte, len(paddedPlainText))
+	for i := 0; i < len(paddedPlainText); i += BlockSize {
+		plainChunk := paddedPlainText[i : i+BlockSize]
+		block.Encrypt(cipherText[i:i+BlockSize], plainChunk)
+	}
+
+	return cipherText, nil
+}
+
+// MustEncryptECB is like EncryptECB but panics on error
+func MustEncryptECB(plainText, key []byte) []byte {
+	cipherText, err := EncryptECB(plainText, key)
+	if err != nil {
+		panic(err)
+	}
+	return cipherText
+}
+
+// DecryptECB decrypts ciphertext with AES in ECB mode and removes the
+// padding
+func DecryptECB(ciphertext, key []byte) ([]byte, error) {
+	block, err := NewAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(ciphertext); err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	for start := 0; start < len(ciphertext); start += BlockSize {
+		block.Decrypt(plaintext[start:start+BlockSize], ciphertext[start:start+BlockSize])
+	}
+
+	return padding.ValidatePKCS7(plaintext, BlockSize)
+}
+
+// MustDecryptECB is like DecryptECB but panics on error
+func MustDecryptECB(ciphertext, key []byte) []byte {
+	plainText, err := DecryptECB(ciphertext, key)
+	if err != nil {
+		panic(err)
+	}
+	return plainText
+}
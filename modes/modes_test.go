@@ -0,0 +1,62 @@
+package modes
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/drio/cryptopals/padding"
+)
+
+func TestEncryptDecryptCBC_Set10(t *testing.T) {
+	t.Run("CBC Encryption and Decryption", func(t *testing.T) {
+		plainText := []byte(`This is a very important secret and should never shared with anyone`)
+		key := []byte("YELLOW SUBMARINE")
+		iv := make([]byte, 16) // 16 zero bytes
+
+		cipherText := MustEncryptCBC(plainText, key, iv)
+		result := MustDecryptCBC(cipherText, key, iv)
+		padRemoved := padding.MustUnpadPKCS7(result)
+
+		if !bytes.Equal(padRemoved, plainText) {
+			t.Errorf("CBC encryption/decryption failed.\nExpected: %s\nGot: %s", plainText, padRemoved)
+		}
+	})
+}
+
+func TestModesErrors(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, 16)
+
+	t.Run("Bad key size", func(t *testing.T) {
+		if _, err := EncryptECB([]byte("foo"), []byte("short")); !errors.Is(err, ErrBadKeySize) {
+			t.Errorf("expected ErrBadKeySize, got %v", err)
+		}
+		if _, err := DecryptCBC(make([]byte, 16), []byte("short"), iv); !errors.Is(err, ErrBadKeySize) {
+			t.Errorf("expected ErrBadKeySize, got %v", err)
+		}
+	})
+
+	t.Run("Bad IV size", func(t *testing.T) {
+		if _, err := EncryptCBC([]byte("foo"), key, []byte("iv")); !errors.Is(err, ErrBadIVSize) {
+			t.Errorf("expected ErrBadIVSize, got %v", err)
+		}
+	})
+
+	t.Run("Not block aligned", func(t *testing.T) {
+		if _, err := DecryptECB(make([]byte, 17), key); !errors.Is(err, ErrNotBlockAligned) {
+			t.Errorf("expected ErrNotBlockAligned, got %v", err)
+		}
+		if _, err := DecryptCBC(make([]byte, 15), key, iv); !errors.Is(err, ErrNotBlockAligned) {
+			t.Errorf("expected ErrNotBlockAligned, got %v", err)
+		}
+	})
+
+	t.Run("Tampered ECB padding", func(t *testing.T) {
+		cipherText := MustEncryptECB([]byte("YELLOW SUBMARINE"), key)
+		cipherText[len(cipherText)-1] ^= 0xff
+		if _, err := DecryptECB(cipherText, key); !errors.Is(err, padding.ErrInvalidPadding) {
+			t.Errorf("expected ErrInvalidPadding, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+// Package oracle implements the encryption oracles the challenges ask us
+// to attack.
+package oracle
+
+import (
+	crand "crypto/rand"
+	"encoding/base64"
+	"fmt"
+	mrand "math/rand"
+
+	"github.com/drio/cryptopals/modes"
+)
+
+// Modes reported by AES
+const (
+	ECB = "ECB"
+	CBC = "CBC"
+)
+
+// RandomBytes creates a slice of size between [min, max] with random
+// bytes in it
+func RandomBytes(min, max int) []byte {
+	to := (max - min) + 1
+	newSlice := make([]byte, min+mrand.Intn(to))
+	crand.Read(newSlice)
+	return newSlice
+}
+
+// RandomAESKey creates a random key for AES
+func RandomAESKey() []byte {
+	k := make([]byte, 16)
+	crand.Read(k)
+	return k
+}
+
+// AES receives a plaintext and:
+//  1. randomly decides if to encrypt in ECB or CBC mode.
+//  2. prepends and appends 5-10 random bytes to your input
+//  3. it uses a new random key (and IV for CBC) every time.
+//
+// It returns the ciphertext and the mode it used.
+func AES(plaintext []byte) ([]byte, string) {
+	return aesOracle(mrand.Intn, func(b []byte) { crand.Read(b) }, plaintext)
+}
+
+// AESFrom is like AES but draws all its randomness from rng, so a seeded
+// rng makes it reproducible
+func AESFrom(rng *mrand.Rand, plaintext []byte) ([]byte, string) {
+	return aesOracle(rng.Intn, func(b []byte) { rng.Read(b) }, plaintext)
+}
+
+// aesOracle implements AES with intn picking the mode and the lengths
+// and read filling the random bytes
+func aesOracle(intn func(int) int, read func([]byte), plaintext []byte) ([]byte, string) {
+	random := func(min, max int) []byte {
+		b := make([]byte, min+intn(max-min+1))
+		read(b)
+		return b
+	}
+
+	// pick a mode
+	mode := ECB
+	if intn(2) == 1 {
+		mode = CBC
+	}
+
+	// add random pre / post
+	pre := random(5, 10)
+	pos := random(5, 10)
+	withPre := append(pre, plaintext...)
+	withPrePos := append(withPre, pos...)
+
+	key := random(16, 16)
+	if mode == ECB {
+		return modes.MustEncryptECB(withPrePos, key), mode
+	}
+
+	iv := []byte("IVIVIV SUBMARINE") // TODO: random
+	return modes.MustEncryptCBC(withPrePos, key, iv), mode
+}
+
+// NewECBSuffix returns an oracle that appends the base64 decoded
+// unknownB64 to its input and encrypts the result with AES-ECB under key
+func NewECBSuffix(key, unknownB64 string) (func([]byte) []byte, error) {
+	unknown, err := base64.StdEncoding.DecodeString(unknownB64)
+	if err != nil {
+		return nil, fmt.Errorf("oracle: decoding unknown string: %w", err)
+	}
+	return NewECBPrefixSuffix([]byte(key), nil, unknown)
+}
+
+// NewECBPrefixSuffix returns an oracle that puts prefix before its input
+// and unknown after it and encrypts the result with AES-ECB under key
+func NewECBPrefixSuffix(key, prefix, unknown []byte) (func([]byte) []byte, error) {
+	if _, err := modes.NewAESCipher(key); err != nil {
+		return nil, err
+	}
+
+	return func(input []byte) []byte {
+		plain := append(append(append([]byte{}, prefix...), input...), unknown...)
+		return modes.MustEncryptECB(plain, key)
+	}, nil
+}
+
+// NewRandomPrefixECB returns the oracle of challenge 14: like
+// NewECBSuffix under a random key, but with a random count (0 to 64) of
+// random bytes before the input too. The prefix is drawn once.
+func NewRandomPrefixECB(unknownB64 string) (func([]byte) []byte, error) {
+	return newRandomPrefixECB(mrand.Intn, func(b []byte) { crand.Read(b) }, unknownB64)
+}
+
+// NewRandomPrefixECBFrom is like NewRandomPrefixECB but draws the key and
+// the prefix from rng
+func NewRandomPrefixECBFrom(rng *mrand.Rand, unknownB64 string) (func([]byte) []byte, error) {
+	return newRandomPrefixECB(rng.Intn, func(b []byte) { rng.Read(b) }, unknownB64)
+}
+
+func newRandomPrefixECB(intn func(int) int, read func([]byte), unknownB64 string) (func([]byte) []byte, error) {
+	unknown, err := base64.StdEncoding.DecodeString(unknownB64)
+	if err != nil {
+		return nil, fmt.Errorf("oracle: decoding unknown string: %w", err)
+	}
+	key := make([]byte, 16)
+	read(key)
+	prefix := make([]byte, intn(65))
+	read(prefix)
+	return NewECBPrefixSuffix(key, prefix, unknown)
+}
+
+// ECBWithPrefix encrypts pre+plaintext with AES in ECB mode using key
+func ECBWithPrefix(pre, plaintext, key []byte) ([]byte, error) {
+	withPre := append(append([]byte{}, pre...), plaintext...)
+	return modes.EncryptECB(withPre, key)
+}
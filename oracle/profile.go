@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/drio/cryptopals/modes"
+)
+
+// ParseKV is the Ch13 key-value parser
+func ParseKV(input string) map[string]string {
+	values, _ := url.ParseQuery(input)
+	result := make(map[string]string)
+	for k, v := range values {
+		result[k] = v[0] // only take the first value
+	}
+	return result
+}
+
+// ProfileFor generates the Ch13 profile for email
+func ProfileFor(email string) string {
+	// Strip '&' and '=' to prevent injection
+	safeEmail := strings.ReplaceAll(strings.ReplaceAll(email, "&", ""), "=", "")
+	return "email=" + safeEmail + "&uid=10&role=user"
+}
+
+// ProfileTool encrypts/decrypts profiles using AES-ECB under a random key
+type ProfileTool struct {
+	key []byte
+}
+
+// NewProfileTool returns a ProfileTool with a fresh random key
+func NewProfileTool() *ProfileTool {
+	return &ProfileTool{key: RandomAESKey()}
+}
+
+// Encrypt encrypts an encoded profile
+func (pt *ProfileTool) Encrypt(profile string) []byte {
+	return modes.MustEncryptECB([]byte(profile), pt.key)
+}
+
+// Decrypt decrypts cipherText and parses the profile in it
+func (pt *ProfileTool) Decrypt(cipherText []byte) (map[string]string, error) {
+	queryString, err := modes.DecryptECB(cipherText, pt.key)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKV(string(queryString)), nil
+}
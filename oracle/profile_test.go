@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKV(t *testing.T) {
+	input := "foo=bar&baz=qux&zap=zazzle"
+	expected := map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+		"zap": "zazzle",
+	}
+
+	result := ParseKV(input)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseKV(%q) = %v; want %v", input, result, expected)
+	}
+}
+
+func TestProfileFor(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected string
+	}{
+		{
+			email:    "foo@bar.com",
+			expected: "email=foo@bar.com&uid=10&role=user",
+		},
+		{
+			email:    "foo@bar.com&role=admin",
+			expected: "email=foo@bar.comroleadmin&uid=10&role=user",
+		},
+		{
+			email:    "a=b@c.com",
+			expected: "email=ab@c.com&uid=10&role=user",
+		},
+	}
+
+	for _, test := range tests {
+		result := ProfileFor(test.email)
+		if result != test.expected {
+			t.Errorf("ProfileFor(%q) =\n[%q];\n want [%q]", test.email, result, test.expected)
+		}
+	}
+}
+
+func TestProfileToolTampered(t *testing.T) {
+	pt := NewProfileTool()
+	cipherText := pt.Encrypt(ProfileFor("foo@bar.com"))
+
+	// the first block ends in 'o', never a valid pad byte
+	swapped := append([]byte{}, cipherText...)
+	copy(swapped[len(swapped)-16:], cipherText[:16])
+
+	tampered := [][]byte{cipherText[:len(cipherText)-1], swapped}
+	for _, ct := range tampered {
+		if _, err := pt.Decrypt(ct); err == nil {
+			t.Errorf("expected an error for a %d byte ciphertext", len(ct))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	crand "crypto/rand"
+	mrand "math/rand"
+	"strings"
+
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/padding"
+)
+
+// The Ch16 cookie fields around the user data
+const (
+	UserDataPrefix = "comment1=cooking%20MCs;userdata="
+	UserDataSuffix = ";comment2=%20like%20a%20pound%20of%20bacon"
+)
+
+// QuoteUserData escapes the ';' and '=' in userData, so it can't add
+// fields of its own to a cookie
+func QuoteUserData(userData string) string {
+	return strings.NewReplacer(";", "%3B", "=", "%3D").Replace(userData)
+}
+
+// UserData is the Ch16 cookie service: it encrypts cookies with AES-CBC
+// under a random key and IV and tells admin cookies apart
+type UserData struct {
+	key, iv []byte
+}
+
+// NewUserData returns a UserData with a fresh random key and IV
+func NewUserData() *UserData {
+	return newUserData(func(b []byte) { crand.Read(b) })
+}
+
+// NewUserDataFrom is like NewUserData but draws the key and IV from rng
+func NewUserDataFrom(rng *mrand.Rand) *UserData {
+	return newUserData(func(b []byte) { rng.Read(b) })
+}
+
+func newUserData(read func([]byte)) *UserData {
+	u := &UserData{key: make([]byte, 16), iv: make([]byte, modes.BlockSize)}
+	read(u.key)
+	read(u.iv)
+	return u
+}
+
+// Encrypt quotes userData, puts it between UserDataPrefix and
+// UserDataSuffix and encrypts the result
+func (u *UserData) Encrypt(userData string) []byte {
+	cookie := UserDataPrefix + QuoteUserData(userData) + UserDataSuffix
+	return modes.MustEncryptCBC([]byte(cookie), u.key, u.iv)
+}
+
+// Decrypt decrypts cipherText and removes the padding
+func (u *UserData) Decrypt(cipherText []byte) ([]byte, error) {
+	plainText, err := modes.DecryptCBC(cipherText, u.key, u.iv)
+	if err != nil {
+		return nil, err
+	}
+	return padding.ValidatePKCS7(plainText, modes.BlockSize)
+}
+
+// IsAdmin decrypts cipherText and reports whether the cookie has an
+// admin=true field
+func (u *UserData) IsAdmin(cipherText []byte) (bool, error) {
+	cookie, err := u.Decrypt(cipherText)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(cookie), ";admin=true;"), nil
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"bytes"
+	mrand "math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUserData(t *testing.T) {
+	t.Run("Quoting", func(t *testing.T) {
+		ud := NewUserData()
+		cookie, err := ud.Decrypt(ud.Encrypt("x;admin=true;"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := UserDataPrefix + "x%3Badmin%3Dtrue%3B" + UserDataSuffix
+		if string(cookie) != expected {
+			t.Errorf("expected %q, got %q", expected, cookie)
+		}
+		if admin, _ := ud.IsAdmin(ud.Encrypt("x;admin=true;")); admin {
+			t.Errorf("quoted input grants admin")
+		}
+	})
+
+	t.Run("Random key and IV", func(t *testing.T) {
+		a, b := NewUserData(), NewUserData()
+		if bytes.Equal(a.Encrypt("x"), b.Encrypt("x")) {
+			t.Errorf("two services encrypt alike")
+		}
+		s1 := NewUserDataFrom(mrand.New(mrand.NewSource(3)))
+		s2 := NewUserDataFrom(mrand.New(mrand.NewSource(3)))
+		if !bytes.Equal(s1.Encrypt("x"), s2.Encrypt("x")) {
+			t.Errorf("the same seed gave different services")
+		}
+	})
+
+	t.Run("Tampered padding", func(t *testing.T) {
+		ud := NewUserData()
+		cipherText := ud.Encrypt(strings.Repeat("A", 10))
+		if _, err := ud.IsAdmin(cipherText[:len(cipherText)-1]); err == nil {
+			t.Errorf("expected an error for a truncated ciphertext")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// Package padding implements PKCS#7 padding.
+package padding
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidPadding is returned when the PKCS#7 padding is malformed
+	ErrInvalidPadding = errors.New("padding: invalid PKCS#7 padding")
+	// ErrBadBlockSize is returned for block sizes PKCS#7 cannot express
+	ErrBadBlockSize = errors.New("padding: block size must be in [1, 255]")
+)
+
+// PadPKCS7 pads plainText with n bytes based on blockSize using PCKS#7
+// Example:
+//
+//	Padding "YELLOW SUBMARINE" with a blockSize of 20:
+//	gives us: "YELLOW SUBMARINE" + "\x04\x04\x04\x04"
+//
+// Even if the plaintext length is already a multiple of 16, we still add
+// a full block of padding (16 bytes). This is required by PKCS#7 so that
+// the unpadding logic can always safely determine and strip padding.
+func PadPKCS7(plainText []byte, blockSize int) ([]byte, error) {
+	if blockSize < 1 || blockSize > 255 {
+		return nil, fmt.Errorf("%w: %d", ErrBadBlockSize, blockSize)
+	}
+
+	padLen := blockSize - (len(plainText) % blockSize)
+	padding := bytes.Repeat([]byte{byte(padLen)}, padLen)
+	return append(plainText, padding...), nil
+}
+
+// MustPadPKCS7 is like PadPKCS7 but panics on error
+func MustPadPKCS7(plainText []byte, blockSize int) []byte {
+	padded, err := PadPKCS7(plainText, blockSize)
+	if err != nil {
+		panic(err)
+	}
+	return padded
+}
+
+// UnpadPKCS7 removes PKCS#7 padding.
+// It is critical to verify that all padding bytes have the correct value;
+// otherwise, we may process invalid data, which can lead to security
+// vulnerabilities like padding oracle attacks.
+// Without a block size it accepts any pad length up to 255; prefer
+// ValidatePKCS7 when the block size is known.
+func UnpadPKCS7(plainText []byte) ([]byte, error) {
+	if len(plainText) == 0 {
+		return nil, fmt.Errorf("%w: input is empty", ErrInvalidPadding)
+	}
+
+	tail := plainText[max(len(plainText)-255, 0):]
+	padLen, ok := checkPad(tail)
+	if !ok {
+		return nil, ErrInvalidPadding
+	}
+	return plainText[:len(plainText)-padLen], nil
+}
+
+// ValidatePKCS7 checks that plainText is a whole number of blocks of
+// blockSize ending in valid PKCS#7 padding, and returns it without the
+// padding. It rejects a zero pad byte, one bigger than blockSize and pad
+// bytes that don't all agree.
+//
+// The check takes the same time for any content of the last block and
+// every bad padding gets the same error, so a caller does not become a
+// padding oracle through timing or error messages. The length and block
+// size are public and checked first.
+func ValidatePKCS7(plainText []byte, blockSize int) ([]byte, error) {
+	if blockSize < 1 || blockSize > 255 {
+		return nil, fmt.Errorf("%w: %d", ErrBadBlockSize, blockSize)
+	}
+	if len(plainText) == 0 || len(plainText)%blockSize != 0 {
+		return nil, fmt.Errorf("%w: length %d is not a multiple of %d", ErrInvalidPadding, len(plainText), blockSize)
+	}
+
+	padLen, ok := checkPad(plainText[len(plainText)-blockSize:])
+	if !ok {
+		return nil, ErrInvalidPadding
+	}
+	return plainText[:len(plainText)-padLen], nil
+}
+
+// MustValidatePKCS7 is like ValidatePKCS7 but panics on error
+func MustValidatePKCS7(plainText []byte, blockSize int) []byte {
+	unpadded, err := ValidatePKCS7(plainText, blockSize)
+	if err != nil {
+		panic(err)
+	}
+	return unpadded
+}
+
+// checkPad reads the pad length off the last byte of tail and checks, in
+// constant time, that it is in [1, len(tail)] and that the last padLen
+// bytes all equal it. Every byte of tail is visited whatever the outcome.
+func checkPad(tail []byte) (int, bool) {
+	padLen := tail[len(tail)-1]
+	good := subtle.ConstantTimeByteEq(padLen, 0) ^ 1
+	good &= subtle.ConstantTimeLessOrEq(int(padLen), len(tail))
+
+	for i := 1; i <= len(tail); i++ {
+		// inPad is 1 for the last padLen bytes, 0 before them
+		inPad := subtle.ConstantTimeLessOrEq(i, int(padLen))
+		same := subtle.ConstantTimeByteEq(tail[len(tail)-i], padLen)
+		good &= same | (inPad ^ 1)
+	}
+
+	return subtle.ConstantTimeSelect(good, int(padLen), 0), good == 1
+}
+
+// MustUnpadPKCS7 is like UnpadPKCS7 but panics on error
+func MustUnpadPKCS7(plainText []byte) []byte {
+	unpadded, err := UnpadPKCS7(plainText)
+	if err != nil {
+		panic(err)
+	}
+	return unpadded
+}
@@ -0,0 +1,132 @@
+package padding
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSet09_PKCS7Padding(t *testing.T) {
+	t.Run("Basic padding test", func(t *testing.T) {
+		expect := []byte("YELLOW SUBMARINE\x04\x04\x04\x04")
+		got := MustPadPKCS7([]byte("YELLOW SUBMARINE"), 20)
+		if len(got) != len(expect) {
+			t.Errorf("wrong size, expecting %d and got %d", 20, len(got))
+		}
+
+		if string(got) != string(expect) {
+			t.Errorf("wrong bytes")
+		}
+	})
+
+	t.Run("Aligned input padding", func(t *testing.T) {
+		input := []byte("0123456789ABCDEF") // 16 bytes
+		expected := append(input, bytes.Repeat([]byte{0x10}, 16)...)
+
+		padded := MustPadPKCS7(input, 16)
+
+		if !bytes.Equal(padded, expected) {
+			t.Errorf("PKCS#7 padding failed.\nExpected: %v\nGot: %v",
+				expected, padded)
+		}
+	})
+}
+
+func TestPadPKCS7BadBlockSize(t *testing.T) {
+	for _, blockSize := range []int{0, -1, 256} {
+		if _, err := PadPKCS7([]byte("YELLOW"), blockSize); !errors.Is(err, ErrBadBlockSize) {
+			t.Errorf("blockSize=%d: expected ErrBadBlockSize, got %v", blockSize, err)
+		}
+	}
+}
+
+func TestUnpadPKCS7(t *testing.T) {
+	t.Run("Valid padding", func(t *testing.T) {
+		data := append([]byte("ICE ICE BABY"), []byte{0x04, 0x04, 0x04, 0x04}...)
+		expected := []byte("ICE ICE BABY")
+
+		unpadded, err := UnpadPKCS7(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(unpadded, expected) {
+			t.Errorf("Unpad failed.\nExpected: %v\nGot: %v", expected, unpadded)
+		}
+	})
+
+	t.Run("Invalid padding", func(t *testing.T) {
+		inputs := [][]byte{
+			{},
+			append([]byte("ICE ICE BABY"), []byte{0x01, 0x02, 0x03, 0x04}...),
+			append([]byte("ICE ICE BABY"), []byte{0x05, 0x05, 0x05, 0x05}...),
+			append([]byte("ICE ICE BABY"), 0x00),
+			{0x05, 0x05},
+		}
+
+		for _, data := range inputs {
+			if _, err := UnpadPKCS7(data); !errors.Is(err, ErrInvalidPadding) {
+				t.Errorf("UnpadPKCS7(%q): expected ErrInvalidPadding, got %v", data, err)
+			}
+		}
+	})
+
+	t.Run("Must panics on invalid padding", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Expected panic due to invalid padding, but got none")
+			}
+		}()
+
+		data := append([]byte("ICE ICE BABY"), []byte{0x01, 0x02, 0x03, 0x04}...)
+		_ = MustUnpadPKCS7(data) // Should panic
+	})
+}
+
+func TestValidatePKCS7(t *testing.T) {
+	t.Run("Challenge 15", func(t *testing.T) {
+		got, err := ValidatePKCS7([]byte("ICE ICE BABY\x04\x04\x04\x04"), 16)
+		if err != nil || string(got) != "ICE ICE BABY" {
+			t.Errorf("expected %q, got %q, %v", "ICE ICE BABY", got, err)
+		}
+		for _, in := range []string{"ICE ICE BABY\x05\x05\x05\x05", "ICE ICE BABY\x01\x02\x03\x04"} {
+			if _, err := ValidatePKCS7([]byte(in), 16); !errors.Is(err, ErrInvalidPadding) {
+				t.Errorf("%q: expected ErrInvalidPadding, got %v", in, err)
+			}
+		}
+	})
+
+	t.Run("Every pad length", func(t *testing.T) {
+		for n := 0; n <= 40; n++ {
+			plainText := bytes.Repeat([]byte{'x'}, n)
+			got, err := ValidatePKCS7(MustPadPKCS7(plainText, 16), 16)
+			if err != nil || !bytes.Equal(got, plainText) {
+				t.Errorf("n=%d: round trip failed: %q, %v", n, got, err)
+			}
+		}
+	})
+
+	t.Run("Invalid padding", func(t *testing.T) {
+		inputs := [][]byte{
+			{},
+			[]byte("ICE ICE BABY\x04\x04\x04"),               // not block aligned
+			[]byte("ICE ICE BABY\x04\x04\x04\x00"),           // zero pad byte
+			bytes.Repeat([]byte{0x11}, 32),                   // bigger than the block
+			append([]byte("0123456789ABCDE"), 0x11),          // bigger than the block
+			append([]byte("ICE ICE BABY\x04\x04\x04"), 0x03), // disagreeing pad bytes
+			append([]byte{0x0f}, bytes.Repeat([]byte{0x10}, 15)...),
+		}
+		for _, in := range inputs {
+			if _, err := ValidatePKCS7(in, 16); !errors.Is(err, ErrInvalidPadding) {
+				t.Errorf("%q: expected ErrInvalidPadding, got %v", in, err)
+			}
+		}
+	})
+
+	t.Run("Bad block size", func(t *testing.T) {
+		for _, blockSize := range []int{0, -1, 256} {
+			if _, err := ValidatePKCS7([]byte("YELLOW SUBMARINE"), blockSize); !errors.Is(err, ErrBadBlockSize) {
+				t.Errorf("blockSize=%d: expected ErrBadBlockSize, got %v", blockSize, err)
+			}
+		}
+	})
+}
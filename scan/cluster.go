@@ -0,0 +1,195 @@
+package scan
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Kinds of shared key a Cluster reports
+const (
+	// Keystream groups ciphertexts XORed with the same keystream from
+	// their first byte: a reused one-time pad, stream cipher nonce or
+	// repeating key
+	Keystream = "keystream"
+	// ECBKey groups ECB ciphertexts encrypted under the same key
+	ECBKey = "ecb"
+)
+
+// DefaultAlpha is the chance ClusterOptions accepts of linking two
+// ciphertexts that don't share a keystream, over all the pairs compared
+const DefaultAlpha = 0.001
+
+// ClusterOptions tunes Clusters
+type ClusterOptions struct {
+	BlockSize       int     // ECB block size (16 when 0)
+	Alpha           float64 // family-wise false link rate of the keystream test (DefaultAlpha when 0)
+	MinOverlap      int     // pairs sharing fewer bytes are not tested for a keystream (16 when 0)
+	MinSharedBlocks int     // blocks two ciphertexts share to be linked as ECB under one key (1 when 0)
+}
+
+// Link is the evidence that two ciphertexts share a key
+type Link struct {
+	A, B  int     // indexes of the ciphertexts, A < B
+	Kind  string  // Keystream or ECBKey
+	Score float64 // Keystream: -log10 of the p-value; ECBKey: shared blocks
+}
+
+// Cluster is a group of ciphertexts that likely share a key. A Keystream
+// cluster is what BreakKeystream in package xor takes: pass it the
+// ciphertexts listed in Members.
+type Cluster struct {
+	Kind    string
+	Members []int  // indexes of the ciphertexts, ascending
+	Links   []Link // the links that joined them
+}
+
+// Clusters groups cipherTexts by shared key, two ways:
+//
+//   - Keystream: XORing two ciphertexts under the same keystream cancels
+//     it, so wherever their plaintexts agree they agree too. Natural
+//     language agrees at about 6% of the positions, where independent keys
+//     give the chance rate of their byte distributions; a pair is linked
+//     when the Poisson tail of its coincidences, corrected for the number
+//     of pairs compared, is below opts.Alpha. Aligned blocks equal in
+//     both are left out of the count.
+//   - ECBKey: ECB encrypts equal blocks alike under one key, and two keys
+//     (or two IVs) practically never produce the same block. A pair is
+//     linked when it shares opts.MinSharedBlocks distinct blocks.
+//
+// Linked ciphertexts end up in the same cluster, also through others.
+// Only clusters of two or more are returned, biggest first.
+func Clusters(cipherTexts [][]byte, opts ClusterOptions) []Cluster {
+	bs := cmp.Or(opts.BlockSize, 16)
+	alpha := cmp.Or(opts.Alpha, DefaultAlpha)
+	minOverlap := cmp.Or(opts.MinOverlap, 16)
+	minShared := cmp.Or(opts.MinSharedBlocks, 1)
+
+	n := len(cipherTexts)
+	freqs := make([][256]float64, n)
+	blocks := make([]map[string]bool, n)
+	for i, ct := range cipherTexts {
+		for _, b := range ct {
+			freqs[i][b]++
+		}
+		for j := range freqs[i] {
+			freqs[i][j] /= float64(max(len(ct), 1))
+		}
+		blocks[i] = map[string]bool{}
+		for j := 0; j+bs <= len(ct); j += bs {
+			blocks[i][string(ct[j:j+bs])] = true
+		}
+	}
+
+	pairs := float64(n) * float64(n-1) / 2
+	var links []Link
+	for a := range n {
+		for b := a + 1; b < n; b++ {
+			if p, ok := keystreamPValue(cipherTexts[a], cipherTexts[b], &freqs[a], &freqs[b], bs, minOverlap); ok && p*pairs < alpha {
+				links = append(links, Link{a, b, Keystream, -math.Log10(max(p, math.SmallestNonzeroFloat64))})
+			}
+			if shared := sharedBlocks(blocks[a], blocks[b]); shared >= minShared {
+				links = append(links, Link{a, b, ECBKey, float64(shared)})
+			}
+		}
+	}
+
+	clusters := append(group(n, Keystream, links), group(n, ECBKey, links)...)
+	slices.SortStableFunc(clusters, func(x, y Cluster) int {
+		return cmp.Or(len(y.Members)-len(x.Members), x.Members[0]-y.Members[0])
+	})
+	return clusters
+}
+
+// keystreamPValue is the chance of a and b agreeing at as many aligned
+// positions as they do were their keys independent, given their byte
+// distributions fa and fb. Blocks of size bs equal in both are left out.
+func keystreamPValue(a, b []byte, fa, fb *[256]float64, bs, minOverlap int) (float64, bool) {
+	overlap := min(len(a), len(b))
+	if overlap < minOverlap {
+		return 1, false
+	}
+
+	// equal blocks are ECB's tell, not evidence of a shared keystream
+	agree, compared := 0, 0
+	for start := 0; start < overlap; start += bs {
+		end := min(start+bs, overlap)
+		if end-start == bs && bytes.Equal(a[start:end], b[start:end]) {
+			continue
+		}
+		for i := start; i < end; i++ {
+			if a[i] == b[i] {
+				agree++
+			}
+		}
+		compared += end - start
+	}
+
+	chance := 0.0
+	for i := range fa {
+		chance += fa[i] * fb[i]
+	}
+	chance = max(chance, 1.0/256)
+	return poissonTail(agree, float64(compared)*chance), true
+}
+
+// sharedBlocks counts the blocks in both a and b
+func sharedBlocks(a, b map[string]bool) int {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	shared := 0
+	for blk := range a {
+		if b[blk] {
+			shared++
+		}
+	}
+	return shared
+}
+
+// group joins the ciphertexts linked by links of kind into clusters
+func group(n int, kind string, links []Link) []Cluster {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, l := range links {
+		if l.Kind == kind {
+			parent[find(l.B)] = find(l.A)
+		}
+	}
+
+	byRoot := map[int]*Cluster{}
+	var roots []int
+	for i := range n {
+		r := find(i)
+		if byRoot[r] == nil {
+			byRoot[r] = &Cluster{Kind: kind}
+			roots = append(roots, r)
+		}
+		byRoot[r].Members = append(byRoot[r].Members, i)
+	}
+	for _, l := range links {
+		if l.Kind == kind {
+			c := byRoot[find(l.A)]
+			c.Links = append(c.Links, l)
+		}
+	}
+
+	var clusters []Cluster
+	for _, r := range roots {
+		if c := byRoot[r]; len(c.Members) > 1 {
+			clusters = append(clusters, *c)
+		}
+	}
+	return clusters
+}
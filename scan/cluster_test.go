@@ -0,0 +1,143 @@
+package scan
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/data"
+	"github.com/drio/cryptopals/modes"
+	"github.com/drio/cryptopals/xor"
+)
+
+func TestClusters(t *testing.T) {
+	lyrics := data.MustReadFile("set1/output6")
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+
+	var cipherTexts [][]byte
+	add := func(ct []byte) int {
+		cipherTexts = append(cipherTexts, ct)
+		return len(cipherTexts) - 1
+	}
+	var expected [][]int
+
+	// a one-time pad used six times
+	pad := random(300)
+	var members []int
+	for i := range 6 {
+		members = append(members, add(xor.MustBytes(lyrics[i*131:i*131+300], pad)))
+	}
+	expected = append(expected, members)
+
+	// five messages under the same repeating key, whose skewed byte
+	// distribution takes longer texts to tell apart from chance
+	members = nil
+	for i := range 5 {
+		pt := lyrics[1000+i*150 : 1000+i*150+600]
+		members = append(members, add(xor.MustRepeat(pt, []byte("Terminator X: Bring the noise"))))
+	}
+	expected = append(expected, members)
+
+	// messages under keys of their own
+	for i := range 5 {
+		pt := lyrics[1800+i*100 : 1800+i*100+300]
+		add(xor.MustBytes(pt, random(300)))
+	}
+
+	// ECB under two keys, with a block of plaintext in common
+	header := []byte("From: Vanilla Ice; To: the crowd")
+	for _, n := range []int{4, 3} {
+		key := random(16)
+		members = nil
+		for i := range n {
+			pt := append(append([]byte{}, header...), lyrics[i*64:i*64+80]...)
+			members = append(members, add(modes.MustEncryptECB(pt, key)))
+		}
+		expected = append(expected, members)
+	}
+
+	clusters := Clusters(cipherTexts, ClusterOptions{})
+	if len(clusters) != len(expected) {
+		t.Fatalf("expected %d clusters, got %d: %+v", len(expected), len(clusters), clusters)
+	}
+	for i, c := range clusters {
+		if !slices.Equal(c.Members, expected[i]) {
+			t.Errorf("cluster %d: expected %v, got %v (%s)", i, expected[i], c.Members, c.Kind)
+		}
+		kind := Keystream
+		if i >= 2 {
+			kind = ECBKey
+		}
+		if c.Kind != kind {
+			t.Errorf("cluster %d: expected kind %s, got %s", i, kind, c.Kind)
+		}
+		for _, l := range c.Links {
+			if l.Kind != c.Kind || l.A >= l.B || !slices.Contains(c.Members, l.A) || !slices.Contains(c.Members, l.B) {
+				t.Errorf("cluster %d: bad link %+v", i, l)
+			}
+		}
+	}
+
+	t.Run("Feeds the keystream breaker", func(t *testing.T) {
+		var group [][]byte
+		for _, m := range clusters[0].Members {
+			group = append(group, cipherTexts[m])
+		}
+		r, err := xor.BreakKeystream(group, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		right := 0
+		for i := range pad {
+			if r.Keystream[i] == pad[i] {
+				right++
+			}
+		}
+		// six ciphertexts deep, most positions can be told
+		if right < len(pad)*2/3 {
+			t.Errorf("only %d of %d keystream bytes right", right, len(pad))
+		}
+	})
+
+	t.Run("No false links", func(t *testing.T) {
+		for _, name := range []string{"set1/4", "set1/8"} {
+			lines, err := data.Lines(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cts [][]byte
+			for _, l := range lines {
+				cts = append(cts, codec.MustHexToBytes(l))
+			}
+			if c := Clusters(cts, ClusterOptions{}); len(c) != 0 {
+				t.Errorf("%s: expected no clusters, got %+v", name, c)
+			}
+		}
+	})
+}
+
+func TestPoissonTail(t *testing.T) {
+	cases := []struct {
+		k        int
+		lambda   float64
+		expected float64
+	}{
+		{0, 2, 1},
+		{1, 2, 1},
+		{3, 1, 1 - math.Exp(-1)*2.5},
+		{10, 0.5, 1.7096e-10},
+	}
+	for _, tc := range cases {
+		got := poissonTail(tc.k, tc.lambda)
+		if math.Abs(got-tc.expected) > tc.expected*1e-3 {
+			t.Errorf("P(X >= %d | %g) = %g, want %g", tc.k, tc.lambda, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/drio/cryptopals/content"
+	"github.com/drio/cryptopals/score"
+	"github.com/drio/cryptopals/xor"
+)
+
+// Detector looks for one kind of structure in a decoded line. Detect
+// returns ok=false when there is nothing to report. Detectors must be safe
+// for concurrent use.
+type Detector interface {
+	Name() string
+	Detect(input []byte) (score float64, detail string, ok bool)
+}
+
+// SingleByteXOR flags the lines that decrypt to plaintext under a single
+// byte key: the best key has to give something content.Classify finds
+// plausible. The score is the one of the best key.
+type SingleByteXOR struct {
+	Scorer score.Scorer // rates the plaintexts (score.English when nil)
+}
+
+// Name implements Detector
+func (SingleByteXOR) Name() string { return "xor" }
+
+// Detect implements Detector
+func (d SingleByteXOR) Detect(input []byte) (float64, string, bool) {
+	r := xor.BreakSingleByteTop(input, d.Scorer, 2)
+	if len(r.Candidates) == 0 || math.IsInf(r.Best().Score, 0) || math.IsNaN(r.Best().Score) {
+		return 0, "", false
+	}
+	best := r.Best()
+	c := content.Classify(best.PlainText)
+	if !c.Plausible() {
+		return 0, "", false
+	}
+	return best.Score, fmt.Sprintf("key=%#02x confidence=%.3f %s %q", best.Key, r.Confidence(), c.Kind, best.PlainText), true
+}
+
+// ECBRepetition flags the lines with more repeated blocks than random
+// data would have, the signature of ECB (see AnalyzeECB). The score is
+// -log10 of the p-value.
+type ECBRepetition struct {
+	BlockSize int     // 16 when 0
+	Alpha     float64 // significance level (DefaultECBAlpha when 0)
+}
+
+// Name implements Detector
+func (ECBRepetition) Name() string { return "ecb" }
+
+// Detect implements Detector
+func (d ECBRepetition) Detect(input []byte) (float64, string, bool) {
+	s, err := AnalyzeECB(input, cmp.Or(d.BlockSize, 16))
+	if err != nil || !s.Significant(cmp.Or(d.Alpha, DefaultECBAlpha)) {
+		return 0, "", false
+	}
+	return -s.LogPValue, s.String(), true
+}
+
+// Entropy flags the lines whose Shannon entropy, in bits per byte, is
+// below Max: ciphertexts from a good cipher look random, so low entropy
+// hints at weak or no encryption. The score is the negated entropy, so
+// the least random lines rank first.
+type Entropy struct {
+	Max float64 // report every line when 0
+}
+
+// Name implements Detector
+func (Entropy) Name() string { return "entropy" }
+
+// Detect implements Detector
+func (d Entropy) Detect(input []byte) (float64, string, bool) {
+	h := ShannonEntropy(input)
+	if d.Max > 0 && h >= d.Max {
+		return 0, "", false
+	}
+	return -h, fmt.Sprintf("%.3f bits/byte", h), true
+}
+
+// ShannonEntropy returns the entropy of the bytes of b in bits per byte
+func ShannonEntropy(b []byte) float64 {
+	return content.Entropy(b)
+}
+
+// Content flags the lines content.Classify recognises as one of Kinds, or
+// as anything plausible when Kinds is empty. The score is the confidence
+// of the classification.
+type Content struct {
+	Kinds []content.Kind
+}
+
+// Name implements Detector
+func (Content) Name() string { return "content" }
+
+// Detect implements Detector
+func (d Content) Detect(input []byte) (float64, string, bool) {
+	c := content.Classify(input)
+	if len(d.Kinds) == 0 && !c.Plausible() || len(d.Kinds) > 0 && !slices.Contains(d.Kinds, c.Kind) {
+		return 0, "", false
+	}
+	detail := c.Kind.String()
+	if c.Format != "" {
+		detail += " " + c.Format
+	}
+	return c.Confidence, detail, true
+}
@@ -0,0 +1,118 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrBlockSize is returned for a block size below 1
+var ErrBlockSize = errors.New("scan: invalid block size")
+
+// DefaultECBAlpha is the p-value under which AnalyzeECB's caller should
+// call a ciphertext ECB. A 16 byte block repeated once in a kilobyte of
+// random data has a p-value around 1e-35, so there is room to spare.
+const DefaultECBAlpha = 1e-9
+
+// ECBStats is the evidence of ECB in a ciphertext: repeated blocks, set
+// against what random data of the same length would produce
+type ECBStats struct {
+	BlockSize int
+	Blocks    int     // full blocks in the ciphertext
+	Repeats   [][]int // indexes of every block seen more than once, a group per distinct block
+	Pairs     int     // pairs of equal blocks
+	Expected  float64 // pairs of equal blocks expected in random data
+	LogPValue float64 // log10 of the chance of Pairs or more in random data
+}
+
+// Duplicates counts the blocks that repeat an earlier one
+func (s ECBStats) Duplicates() int {
+	dups := 0
+	for _, r := range s.Repeats {
+		dups += len(r) - 1
+	}
+	return dups
+}
+
+// PValue is the chance of as many equal blocks in random data. It
+// underflows to 0 for strong evidence; compare LogPValue then.
+func (s ECBStats) PValue() float64 {
+	return math.Pow(10, s.LogPValue)
+}
+
+// Significant reports whether the repeats are too many for random data at
+// the significance level alpha
+func (s ECBStats) Significant(alpha float64) bool {
+	return s.Pairs > 0 && s.LogPValue < math.Log10(alpha)
+}
+
+func (s ECBStats) String() string {
+	return fmt.Sprintf("%d repeated %d byte blocks of %d %v, p=%.3g (%.3g pairs expected at random)",
+		s.Duplicates(), s.BlockSize, s.Blocks, s.Repeats, s.PValue(), s.Expected)
+}
+
+// AnalyzeECB looks for repeated blocks of blockSize bytes (8 for DES and
+// Blowfish, 16 for AES, 32 for Rijndael-256) in cipherText. A good cipher
+// in any other mode repeats a block only by chance: n blocks hold about
+// n(n-1)/2 / 2^(8 blockSize) equal pairs, and the number of pairs is
+// Poisson distributed. That gives the p-value of what is seen, which
+// stays high for short messages of small blocks.
+func AnalyzeECB(cipherText []byte, blockSize int) (ECBStats, error) {
+	if blockSize < 1 {
+		return ECBStats{}, fmt.Errorf("%w: %d", ErrBlockSize, blockSize)
+	}
+
+	s := ECBStats{BlockSize: blockSize, Blocks: len(cipherText) / blockSize}
+	seen := map[string]int{} // block → index in s.Repeats, -1 when seen once
+	first := map[string]int{}
+	for i := range s.Blocks {
+		blk := string(cipherText[i*blockSize : (i+1)*blockSize])
+		j, ok := seen[blk]
+		switch {
+		case !ok:
+			seen[blk] = -1
+			first[blk] = i
+		case j < 0:
+			seen[blk] = len(s.Repeats)
+			s.Repeats = append(s.Repeats, []int{first[blk], i})
+		default:
+			s.Repeats[j] = append(s.Repeats[j], i)
+		}
+	}
+	for _, r := range s.Repeats {
+		s.Pairs += len(r) * (len(r) - 1) / 2
+	}
+
+	// log10 of n(n-1)/2 / 2^(8 blockSize), without overflowing
+	n := float64(s.Blocks)
+	logExpected := math.Log10(n*(n-1)/2) - 8*float64(blockSize)*math.Log10(2)
+	s.Expected = math.Pow(10, logExpected)
+	s.LogPValue = logPoissonTail(s.Pairs, logExpected)
+	return s, nil
+}
+
+// logPoissonTail returns log10 P(X >= k) for X ~ Poisson(lambda), given
+// log10 lambda, so that tiny lambdas and tails don't underflow. At or
+// below the mean it returns 0 (P = 1), close enough for a significance
+// test.
+func logPoissonTail(k int, logLambda float64) float64 {
+	lambda := math.Pow(10, logLambda)
+	if k <= 0 || float64(k) <= lambda {
+		return 0
+	}
+	lgamma, _ := math.Lgamma(float64(k + 1))
+	logTerm := float64(k)*logLambda - (lambda+lgamma)/math.Ln10
+
+	// the tail is the k-th term times 1 + lambda/(k+1) + ...
+	sum, ratio := 1.0, 1.0
+	for i := k + 1; ratio > sum*1e-12; i++ {
+		ratio *= lambda / float64(i)
+		sum += ratio
+	}
+	return min(logTerm+math.Log10(sum), 0)
+}
+
+// poissonTail returns P(X >= k) for X ~ Poisson(lambda)
+func poissonTail(k int, lambda float64) float64 {
+	return math.Pow(10, logPoissonTail(k, math.Log10(lambda)))
+}
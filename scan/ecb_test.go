@@ -0,0 +1,131 @@
+package scan
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/data"
+	"github.com/drio/cryptopals/modes"
+)
+
+func TestAnalyzeECB(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+
+	t.Run("Positions", func(t *testing.T) {
+		a, b := random(16), random(16)
+		cipherText := slices.Concat(a, random(16), b, a, random(16), b, a, random(5))
+		s, err := AnalyzeECB(cipherText, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Blocks != 7 || s.Duplicates() != 3 || s.Pairs != 4 {
+			t.Errorf("expected 7 blocks, 3 duplicates, 4 pairs; got %+v", s)
+		}
+		if expected := [][]int{{0, 3, 6}, {2, 5}}; !slices.EqualFunc(s.Repeats, expected, slices.Equal) {
+			t.Errorf("expected repeats %v, got %v", expected, s.Repeats)
+		}
+		if !s.Significant(DefaultECBAlpha) {
+			t.Errorf("expected significant repeats, p=%g", s.PValue())
+		}
+	})
+
+	t.Run("Block sizes", func(t *testing.T) {
+		plainText := bytes.Repeat([]byte("YELLOW SUBMARINE"), 8)
+		for _, bs := range []int{8, 16, 32} {
+			// ECB under a toy cipher: every block XORed with the key
+			key := random(bs)
+			cipherText := make([]byte, len(plainText))
+			for i := range plainText {
+				cipherText[i] = plainText[i] ^ key[i%bs]
+			}
+			s, err := AnalyzeECB(cipherText, bs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			distinct := 1
+			if bs == 8 {
+				distinct = 2 // "YELLOW S" and "UBMARINE"
+			}
+			if !s.Significant(DefaultECBAlpha) || s.Duplicates() != s.Blocks-distinct {
+				t.Errorf("%d byte blocks: expected %d duplicates, got %s", bs, s.Blocks-distinct, s)
+			}
+		}
+	})
+
+	t.Run("No false positives", func(t *testing.T) {
+		// the birthday bound of 8 byte blocks is 2^32 blocks away
+		for _, bs := range []int{8, 16, 32} {
+			for _, size := range []int{0, 7, 8, 64, 4096, 1 << 20} {
+				s, err := AnalyzeECB(random(size), bs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s.Significant(DefaultECBAlpha) {
+					t.Errorf("%d bytes of %d byte blocks flagged: %s", size, bs, s)
+				}
+			}
+		}
+
+		// CBC of repetitive plaintext
+		cipherText, err := modes.EncryptCBC(bytes.Repeat([]byte("A"), 256), random(16), random(16))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, _ := AnalyzeECB(cipherText, 16); s.Significant(DefaultECBAlpha) {
+			t.Errorf("CBC flagged: %s", s)
+		}
+	})
+
+	t.Run("Short collisions", func(t *testing.T) {
+		// a chance repeat in a sea of 8 byte blocks is no proof...
+		blocks := float64(1 << 31)
+		expected := blocks * (blocks - 1) / 2 / math.Pow(2, 64)
+		if lp := logPoissonTail(1, math.Log10(expected)); lp < -1 {
+			t.Errorf("a single repeat among 2^31 blocks has log10 p = %g", lp)
+		}
+		// ...but one in a short message is
+		s, _ := AnalyzeECB(bytes.Repeat([]byte{1}, 16), 8)
+		if !s.Significant(DefaultECBAlpha) {
+			t.Errorf("expected a significant repeat: %s", s)
+		}
+	})
+
+	t.Run("Challenge 8", func(t *testing.T) {
+		lines, err := data.Lines("set1/8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		flagged := 0
+		for i, l := range lines {
+			s, err := AnalyzeECB(codec.MustHexToBytes(l), 16)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Significant(DefaultECBAlpha) {
+				flagged++
+				if i+1 != 133 {
+					t.Errorf("line %d flagged: %s", i+1, s)
+				}
+			}
+		}
+		if flagged != 1 {
+			t.Errorf("expected one line flagged, got %d", flagged)
+		}
+	})
+
+	t.Run("Invalid block size", func(t *testing.T) {
+		if _, err := AnalyzeECB([]byte("x"), 0); !errors.Is(err, ErrBlockSize) {
+			t.Errorf("expected ErrBlockSize, got %v", err)
+		}
+	})
+}
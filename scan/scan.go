@@ -0,0 +1,249 @@
+// Package scan runs detectors over large dumps of ciphertexts, one hex or
+// base64 encoded ciphertext per line, on a pool of workers.
+package scan
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/drio/cryptopals/codec"
+)
+
+// Encoding tells the scanner how the lines are encoded
+type Encoding int
+
+const (
+	Auto   Encoding = iota // hex when the line looks like hex, base64 otherwise
+	Hex                    // hex
+	Base64                 // standard base64
+)
+
+// DefaultMaxLineSize is the longest line Scan reads unless told otherwise
+const DefaultMaxLineSize = 1 << 20
+
+// ErrNoDetectors is returned when Scan has nothing to run
+var ErrNoDetectors = errors.New("scan: no detectors")
+
+// Finding is a line a detector flagged. The higher the Score, the more
+// likely the detector is right; scores of different detectors are not
+// comparable.
+type Finding struct {
+	Line     int     // 1 based line number
+	Detector string  // name of the detector
+	Score    float64 // detector specific, higher is better
+	Detail   string  // human readable summary
+	Input    []byte  // the decoded line
+}
+
+// Options tunes Scan
+type Options struct {
+	Workers     int        // size of the worker pool (GOMAXPROCS when 0)
+	Encoding    Encoding   // how the lines are encoded
+	Detectors   []Detector // what to look for in every line
+	MaxLineSize int        // longest line accepted (DefaultMaxLineSize when 0)
+	SkipInvalid bool       // skip the lines that don't decode instead of failing
+}
+
+type job struct {
+	line int
+	text string
+}
+
+type result struct {
+	findings []Finding
+	err      error
+}
+
+// Scan reads r line by line, decodes every line and runs the detectors of
+// opts on it, on opts.Workers goroutines. fn is called with every finding
+// as soon as it is found, one call at a time but not in line order.
+// Scan stops at the first error, the first error returned by fn, or when
+// ctx is done; a read blocked on r is not interrupted.
+func Scan(ctx context.Context, r io.Reader, opts Options, fn func(Finding) error) error {
+	if len(opts.Detectors) == 0 {
+		return ErrNoDetectors
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	maxLine := opts.MaxLineSize
+	if maxLine < 1 {
+		maxLine = DefaultMaxLineSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan job, workers)
+	results := make(chan result, workers)
+	var readErr error
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		defer close(jobs)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+		lineNumber := 0
+		for scanner.Scan() {
+			lineNumber++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			select {
+			case jobs <- job{lineNumber, text}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			readErr = fmt.Errorf("scan: line %d: %w", lineNumber+1, err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res := process(j, opts)
+				if len(res.findings) == 0 && res.err == nil {
+					continue
+				}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	for res := range results {
+		if err != nil {
+			continue // drain
+		}
+		err = res.err
+		for _, f := range res.findings {
+			if err != nil {
+				break
+			}
+			err = fn(f)
+		}
+		if err != nil {
+			cancel()
+		}
+	}
+	<-readDone
+	if err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+	return context.Cause(ctx)
+}
+
+// process decodes one line and runs every detector on it
+func process(j job, opts Options) result {
+	input, err := decode(j.text, opts.Encoding)
+	if err != nil {
+		if opts.SkipInvalid {
+			return result{}
+		}
+		return result{err: fmt.Errorf("scan: line %d: %w", j.line, err)}
+	}
+
+	var findings []Finding
+	for _, d := range opts.Detectors {
+		score, detail, ok := d.Detect(input)
+		if !ok {
+			continue
+		}
+		findings = append(findings, Finding{j.line, d.Name(), score, detail, input})
+	}
+	return result{findings: findings}
+}
+
+// decode decodes a line with enc
+func decode(text string, enc Encoding) ([]byte, error) {
+	switch enc {
+	case Hex:
+		return codec.HexToBytes(text)
+	case Base64:
+		return codec.Base64ToBytes(text)
+	}
+	if isHex(text) {
+		return codec.HexToBytes(text)
+	}
+	return codec.Base64ToBytes(text)
+}
+
+// isHex reports whether s is an even number of hex digits
+func isHex(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	for i := range len(s) {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// Top scans r like Scan and returns the n best findings of every
+// detector: grouped in the order of opts.Detectors, best first. Ties go to
+// the earliest line, so the result doesn't depend on the scheduling.
+func Top(ctx context.Context, r io.Reader, opts Options, n int) ([]Finding, error) {
+	best := map[string][]Finding{}
+	err := Scan(ctx, r, opts, func(f Finding) error {
+		ranked := best[f.Detector]
+		i, _ := slices.BinarySearchFunc(ranked, f, compareFindings)
+		if i >= n {
+			return nil
+		}
+		ranked = slices.Insert(ranked, i, f)
+		if len(ranked) > n {
+			ranked = ranked[:n]
+		}
+		best[f.Detector] = ranked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for _, d := range opts.Detectors {
+		findings = append(findings, best[d.Name()]...)
+		delete(best, d.Name())
+	}
+	return findings, nil
+}
+
+// compareFindings orders findings from best to worst
+func compareFindings(a, b Finding) int {
+	switch {
+	case a.Score > b.Score:
+		return -1
+	case a.Score < b.Score:
+		return 1
+	}
+	return a.Line - b.Line
+}
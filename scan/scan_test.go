@@ -0,0 +1,211 @@
+package scan
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/content"
+	"github.com/drio/cryptopals/data"
+	"github.com/drio/cryptopals/xor"
+)
+
+func openData(t *testing.T, name string) fs.File {
+	t.Helper()
+	f, err := data.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestTop(t *testing.T) {
+	t.Run("Challenge 4", func(t *testing.T) {
+		for _, workers := range []int{1, 4, 16} {
+			opts := Options{Workers: workers, Encoding: Hex, Detectors: []Detector{SingleByteXOR{}}}
+			top, err := Top(context.Background(), openData(t, "set1/4"), opts, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(top) != 3 {
+				t.Fatalf("expected 3 findings, got %d", len(top))
+			}
+			if top[0].Line != 171 || !strings.Contains(top[0].Detail, "Now that the party is jumping") {
+				t.Errorf("%d workers: wrong finding %+v", workers, top[0])
+			}
+			if top[1].Score > top[0].Score || top[2].Score > top[1].Score {
+				t.Errorf("findings are not ranked")
+			}
+		}
+	})
+
+	t.Run("Challenge 8", func(t *testing.T) {
+		opts := Options{Detectors: []Detector{ECBRepetition{}, Entropy{}}}
+		top, err := Top(context.Background(), openData(t, "set1/8"), opts, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(top) != 2 {
+			t.Fatalf("expected a finding per detector, got %d", len(top))
+		}
+		for _, f := range top {
+			// the ECB line has the least entropy too
+			if f.Line != 133 {
+				t.Errorf("%s: expected line 133, got %d", f.Detector, f.Line)
+			}
+		}
+		if top[0].Detector != "ecb" || top[0].Score < 200 || !strings.Contains(top[0].Detail, "[[1 3 5 7]]") {
+			t.Errorf("wrong ECB finding %+v", top[0])
+		}
+	})
+
+	t.Run("Base64 lines", func(t *testing.T) {
+		lines := []string{}
+		for _, s := range []string{"random garbage \x8f\x01", "attack at dawn", "\xff\xfe\xfd\x10"} {
+			lines = append(lines, base64.StdEncoding.EncodeToString(xor.MustRepeat([]byte(s), []byte{0x42})))
+		}
+		opts := Options{Detectors: []Detector{SingleByteXOR{}}}
+		top, err := Top(context.Background(), strings.NewReader(strings.Join(lines, "\n")), opts, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if top[0].Line != 2 || string(top[0].Input) != string(xor.MustRepeat([]byte("attack at dawn"), []byte{0x42})) {
+			t.Errorf("wrong finding %+v", top[0])
+		}
+	})
+}
+
+func TestContentDetector(t *testing.T) {
+	random := make([]byte, 256)
+	rand.New(rand.NewSource(1)).Read(random)
+	lines := []string{}
+	for _, b := range [][]byte{random, []byte(`{"user": "alice", "id": 1234}`), []byte("\x00\x01\x02\x03\x04\x05"), []byte("attack at dawn")} {
+		lines = append(lines, base64.StdEncoding.EncodeToString(b))
+	}
+	input := strings.Join(lines, "\n")
+
+	top, err := Top(context.Background(), strings.NewReader(input), Options{Detectors: []Detector{Content{}}}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 {
+		t.Fatalf("expected the JSON and English lines, got %+v", top)
+	}
+
+	opts := Options{Detectors: []Detector{Content{Kinds: []content.Kind{content.JSON}}}}
+	top, err = Top(context.Background(), strings.NewReader(input), opts, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 1 || top[0].Line != 2 || top[0].Detail != "json" {
+		t.Errorf("expected the JSON line, got %+v", top)
+	}
+}
+
+func TestScan(t *testing.T) {
+	opts := Options{Detectors: []Detector{Entropy{}}}
+
+	t.Run("Every line", func(t *testing.T) {
+		seen := map[int]bool{}
+		err := Scan(context.Background(), openData(t, "set1/4"), opts, func(f Finding) error {
+			if seen[f.Line] {
+				t.Errorf("line %d reported twice", f.Line)
+			}
+			seen[f.Line] = true
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(seen) != 327 {
+			t.Errorf("expected 327 findings, got %d", len(seen))
+		}
+	})
+
+	t.Run("Invalid lines", func(t *testing.T) {
+		input := "0102\nnot hex nor base64!\n0304\n"
+		err := Scan(context.Background(), strings.NewReader(input), opts, func(Finding) error { return nil })
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected an error on line 2, got %v", err)
+		}
+
+		count := 0
+		skip := opts
+		skip.SkipInvalid = true
+		err = Scan(context.Background(), strings.NewReader(input), skip, func(Finding) error {
+			count++
+			return nil
+		})
+		if err != nil || count != 2 {
+			t.Errorf("expected 2 findings and no error, got %d, %v", count, err)
+		}
+	})
+
+	t.Run("Callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := Scan(context.Background(), openData(t, "set1/4"), opts, func(Finding) error {
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("expected errStop, got %v", err)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		// an endless stream of lines
+		r, w := io.Pipe()
+		go func() {
+			line := []byte(codec.ToHex([]byte("YELLOW SUBMARINE")) + "\n")
+			for {
+				if _, err := w.Write(line); err != nil {
+					return
+				}
+			}
+		}()
+		defer r.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		count := 0
+		err := Scan(ctx, r, opts, func(Finding) error {
+			if count++; count == 100 {
+				cancel()
+			}
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("No detectors", func(t *testing.T) {
+		err := Scan(context.Background(), strings.NewReader("00"), Options{}, nil)
+		if !errors.Is(err, ErrNoDetectors) {
+			t.Errorf("expected ErrNoDetectors, got %v", err)
+		}
+	})
+}
+
+func TestShannonEntropy(t *testing.T) {
+	cases := []struct {
+		input    []byte
+		expected float64
+	}{
+		{nil, 0},
+		{[]byte("aaaa"), 0},
+		{[]byte("abab"), 1},
+		{[]byte("abcdefgh"), 3},
+	}
+	for _, tc := range cases {
+		if h := ShannonEntropy(tc.input); math.Abs(h-tc.expected) > 1e-9 {
+			t.Errorf("%q: expected %f, got %f", tc.input, tc.expected, h)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package score
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed langs/*.json
+var langFS embed.FS
+
+// Log probabilities given to symbols a profile has never seen
+const (
+	unseenPrintable = -9.0  // ~1e-4
+	unseenControl   = -16.0 // ~1e-7, also used for invalid UTF-8
+)
+
+// Profile holds the letter and bigram statistics of a language (or of a
+// kind of text such as source code). Letters are lower case runes, so the
+// statistics work for any UTF-8 text. A Profile scores contiguous text;
+// use Bytes for the byte columns of a repeating-key break.
+type Profile struct {
+	Name string
+
+	unigrams map[rune]float64   // log probabilities
+	bigrams  map[string]float64 // log(P(ab) / P(a)P(b)), the bigram bonus
+	bytes    *byteModel
+}
+
+// profileFile is the on-disk format of a profile: relative weights that
+// are normalized on load
+type profileFile struct {
+	Name    string             `json:"name"`
+	Letters map[string]float64 `json:"letters"`
+	Bigrams map[string]float64 `json:"bigrams,omitempty"`
+}
+
+// LoadProfile reads a profile in JSON format:
+//
+//	{"name": "spanish", "letters": {"e": 13.68, ...}, "bigrams": {"de": 2.57, ...}}
+//
+// Letters are single runes; the weights are relative and need not add up
+// to anything in particular.
+func LoadProfile(r io.Reader) (*Profile, error) {
+	var pf profileFile
+	if err := json.NewDecoder(r).Decode(&pf); err != nil {
+		return nil, fmt.Errorf("score: decoding profile: %w", err)
+	}
+
+	letters := map[rune]float64{}
+	for s, w := range pf.Letters {
+		r, size := utf8.DecodeRuneInString(s)
+		if size != len(s) || r == utf8.RuneError {
+			return nil, fmt.Errorf("score: profile %q: %q is not a single rune", pf.Name, s)
+		}
+		letters[unicode.ToLower(r)] += w
+	}
+	return newProfile(pf.Name, letters, pf.Bigrams)
+}
+
+// TrainProfile builds a profile named name with the rune and bigram
+// statistics of corpus
+func TrainProfile(name string, corpus io.Reader) (*Profile, error) {
+	data, err := io.ReadAll(corpus)
+	if err != nil {
+		return nil, fmt.Errorf("score: reading corpus: %w", err)
+	}
+
+	letters := map[rune]float64{}
+	bigrams := map[string]float64{}
+	prev := rune(-1)
+	for _, r := range strings.ToLower(string(data)) {
+		if r == utf8.RuneError {
+			prev = -1
+			continue
+		}
+		letters[r]++
+		if prev >= 0 {
+			bigrams[string([]rune{prev, r})]++
+		}
+		prev = r
+	}
+	return newProfile(name, letters, bigrams)
+}
+
+func newProfile(name string, letters map[rune]float64, bigrams map[string]float64) (*Profile, error) {
+	total := 0.0
+	for _, w := range letters {
+		total += w
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("%w: profile %q has no letters", ErrEmptyCorpus, name)
+	}
+
+	p := &Profile{
+		Name:     name,
+		unigrams: make(map[rune]float64, len(letters)),
+		bigrams:  make(map[string]float64, len(bigrams)),
+	}
+	for r, w := range letters {
+		p.unigrams[r] = math.Log(w / total)
+	}
+
+	bTotal := 0.0
+	for _, w := range bigrams {
+		bTotal += w
+	}
+	for g, w := range bigrams {
+		rs := []rune(strings.ToLower(g))
+		if len(rs) != 2 || w <= 0 {
+			continue
+		}
+		pmi := math.Log(w/bTotal) - p.logProb(rs[0]) - p.logProb(rs[1])
+		p.bigrams[string(rs)] = max(pmi, 0)
+	}
+
+	p.bytes = newByteModel(letters)
+	return p, nil
+}
+
+// logProb returns the log probability of the (lower case) rune r
+func (p *Profile) logProb(r rune) float64 {
+	if lp, ok := p.unigrams[r]; ok {
+		return lp
+	}
+	if r == utf8.RuneError || !unicode.IsPrint(r) {
+		return unseenControl
+	}
+	return unseenPrintable
+}
+
+// Score returns the mean log-likelihood per rune of text under the
+// profile, with a bonus for its common bigrams. Scores of different
+// profiles are comparable, which is what language detection relies on.
+func (p *Profile) Score(text []byte) float64 {
+	if len(text) == 0 {
+		return math.Inf(-1)
+	}
+
+	sum := 0.0
+	n := 0
+	prev := rune(-1)
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+		r = unicode.ToLower(r)
+		sum += p.logProb(r)
+		if prev >= 0 {
+			sum += p.bigrams[string([]rune{prev, r})]
+		}
+		prev = r
+		n++
+	}
+	return sum / float64(n)
+}
+
+// Accept reports whether text is mostly valid, printable UTF-8
+func (p *Profile) Accept(text []byte) bool {
+	good, n := 0, 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+		n++
+		if r != utf8.RuneError && (unicode.IsPrint(r) || unicode.IsSpace(r)) {
+			good++
+		}
+	}
+	return n > 0 && float64(good)/float64(n) > 0.9
+}
+
+// Bytes returns a byte level view of the profile. It scores text that is
+// not contiguous, such as the transposed columns of a repeating-key XOR
+// ciphertext where the bytes of a multi-byte rune end up in different
+// columns.
+func (p *Profile) Bytes() Scorer {
+	return p.bytes
+}
+
+// byteModel is the distribution of the bytes of the UTF-8 encoding of a
+// profile's letters
+type byteModel struct {
+	logProb [256]float64
+	allowed [256]bool // bytes the profile produces
+}
+
+func newByteModel(letters map[rune]float64) *byteModel {
+	counts := [256]float64{}
+	total := 0.0
+	for r, w := range letters {
+		buf := utf8.AppendRune(nil, r)
+		for _, b := range buf {
+			counts[b] += w
+			total += w
+		}
+		// the upper case version shares the weight of the lower case one
+		if u := unicode.ToUpper(r); u != r {
+			for _, b := range utf8.AppendRune(nil, u) {
+				counts[b] += w * 0.05
+				total += w * 0.05
+			}
+		}
+	}
+
+	m := &byteModel{}
+	for b := range counts {
+		if counts[b] > 0 {
+			m.logProb[b] = math.Log(counts[b] / total)
+			m.allowed[b] = true
+			continue
+		}
+		m.logProb[b] = unseenControl
+		if b >= 32 && b <= 126 {
+			m.logProb[b] = unseenPrintable
+			m.allowed[b] = true
+		}
+	}
+	return m
+}
+
+// Score returns the mean log probability per byte
+func (m *byteModel) Score(text []byte) float64 {
+	if len(text) == 0 {
+		return math.Inf(-1)
+	}
+	sum := 0.0
+	for _, b := range text {
+		sum += m.logProb[b]
+	}
+	return sum / float64(len(text))
+}
+
+// Accept reports whether at least 90% of the bytes are ones the profile
+// produces (printable ASCII, whitespace or the bytes of its letters)
+func (m *byteModel) Accept(text []byte) bool {
+	good := 0
+	for _, b := range text {
+		if m.allowed[b] {
+			good++
+		}
+	}
+	return len(text) > 0 && float64(good)/float64(len(text)) > 0.9
+}
+
+// Profiles returns the built-in profiles: english, spanish, french,
+// german and code (source code and JSON)
+func Profiles() []*Profile {
+	entries, err := langFS.ReadDir("langs")
+	if err != nil {
+		panic(err)
+	}
+	profiles := []*Profile{}
+	for _, e := range entries {
+		p, err := Builtin(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			panic(err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// Builtin returns the built-in profile called name
+func Builtin(name string) (*Profile, error) {
+	f, err := langFS.Open(path.Join("langs", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("score: no built-in profile %q", name)
+	}
+	defer f.Close()
+	return LoadProfile(f)
+}
+
+// Detect returns the profile under which text scores best, and that
+// score
+func Detect(text []byte, profiles []*Profile) (*Profile, float64) {
+	var best *Profile
+	bestScore := math.Inf(-1)
+	for _, p := range profiles {
+		if s := p.Score(text); best == nil || s > bestScore {
+			best, bestScore = p, s
+		}
+	}
+	return best, bestScore
+}
+
+// Auto scores a text under every profile and keeps the best score, so
+// the XOR breakers pick the key and the language at the same time
+type Auto []*Profile
+
+// Score returns the best score of text across the profiles
+func (a Auto) Score(text []byte) float64 {
+	_, s := Detect(text, a)
+	return s
+}
+
+// Accept reports whether any profile accepts text
+func (a Auto) Accept(text []byte) bool {
+	for _, p := range a {
+		if p.Accept(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the profiles, sorted
+func Names(profiles []*Profile) []string {
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// binaryShare is the probability mass NewBinary gives to the bytes of
+// binary structure (lengths, tags, NUL padding)
+const binaryShare = 0.3
+
+// NewBinary returns a byte level scorer for binary-ish plaintexts: the
+// text bytes follow p while the rest of the mass goes to the bytes that
+// make up binary records. NUL is the most common of those, followed by
+// small integers and 0xff. Unlike the text models it does not mistake
+// NUL padding for spaces.
+func NewBinary(p *Profile) Scorer {
+	m := &byteModel{}
+	structure := [256]float64{0x00: 40, 0xff: 5}
+	for b := 0x01; b < 0x20; b++ {
+		structure[b] = 1.5
+	}
+	total := 0.0
+	for b := range structure {
+		structure[b] += 0.05 // every byte value shows up in binary data
+		total += structure[b]
+	}
+
+	for b := range m.logProb {
+		text := 0.0
+		if p.bytes.allowed[b] {
+			text = math.Exp(p.bytes.logProb[b])
+		}
+		m.logProb[b] = math.Log((1-binaryShare)*text + binaryShare*structure[b]/total)
+		m.allowed[b] = true
+	}
+	return m
+}
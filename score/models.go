@@ -0,0 +1,249 @@
+package score
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// ErrEmptyCorpus is returned when a model is trained on a corpus with no
+// usable data
+var ErrEmptyCorpus = errors.New("score: empty corpus")
+
+// lower maps ASCII upper case letters to lower case
+func lower(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// ChiSquared compares the byte distribution of a text with the expected
+// one using Pearson's chi-squared statistic. Score returns the negated
+// statistic so better fits score higher.
+type ChiSquared struct {
+	expected [256]float64 // probability of each (lower cased) byte
+}
+
+// smoothing is the pseudo-count given to bytes never seen in training
+const smoothing = 0.01
+
+// NewChiSquared builds the model from a table of character frequencies.
+// Bytes not in the table get a small probability so that unexpected
+// symbols are penalized without making the statistic infinite.
+func NewChiSquared(freqs map[rune]float64) *ChiSquared {
+	counts := [256]float64{}
+	for r, f := range freqs {
+		if r < 256 {
+			counts[lower(byte(r))] += f * 1000
+		}
+	}
+	return newChiSquared(counts)
+}
+
+func newChiSquared(counts [256]float64) *ChiSquared {
+	total := 0.0
+	for b := range counts {
+		counts[b] += smoothing
+		total += counts[b]
+	}
+	cs := &ChiSquared{}
+	for b := range counts {
+		cs.expected[b] = counts[b] / total
+	}
+	return cs
+}
+
+// TrainChiSquared learns the byte distribution of a corpus
+func TrainChiSquared(corpus io.Reader) (*ChiSquared, error) {
+	counts := [256]float64{}
+	n := 0
+	br := bufio.NewReader(corpus)
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("score: reading corpus: %w", err)
+		}
+		counts[lower(b)]++
+		n++
+	}
+	if n == 0 {
+		return nil, ErrEmptyCorpus
+	}
+	return newChiSquared(counts), nil
+}
+
+// Score returns minus the chi-squared statistic, normalized by the text
+// length so texts of different sizes are comparable
+func (cs *ChiSquared) Score(text []byte) float64 {
+	if len(text) == 0 {
+		return math.Inf(-1)
+	}
+
+	observed := [256]float64{}
+	for _, b := range text {
+		observed[lower(b)]++
+	}
+
+	n := float64(len(text))
+	chi2 := 0.0
+	for b := range observed {
+		expected := cs.expected[b] * n
+		d := observed[b] - expected
+		chi2 += d * d / expected
+	}
+	return -chi2 / n
+}
+
+// NGram scores a text with the average log-likelihood of its (lower
+// cased) n-grams under a model trained on a corpus
+type NGram struct {
+	n       int
+	logProb map[string]float64
+	floor   float64 // log probability of unseen n-grams
+}
+
+// TrainNGram counts the n-grams in corpus. n is usually 2 (bigrams) or 3
+// (trigrams).
+func TrainNGram(corpus io.Reader, n int) (*NGram, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("score: invalid n-gram size %d", n)
+	}
+	data, err := io.ReadAll(corpus)
+	if err != nil {
+		return nil, fmt.Errorf("score: reading corpus: %w", err)
+	}
+	data = bytes.ToLower(data)
+	if len(data) < n {
+		return nil, ErrEmptyCorpus
+	}
+
+	counts := map[string]float64{}
+	total := 0.0
+	for i := 0; i+n <= len(data); i++ {
+		counts[string(data[i:i+n])]++
+		total++
+	}
+
+	m := &NGram{
+		n:       n,
+		logProb: make(map[string]float64, len(counts)),
+		floor:   math.Log(0.01 / total),
+	}
+	for g, c := range counts {
+		m.logProb[g] = math.Log(c / total)
+	}
+	return m, nil
+}
+
+// Score returns the mean log probability of the n-grams in text
+func (m *NGram) Score(text []byte) float64 {
+	if len(text) < m.n {
+		return m.floor
+	}
+
+	lowered := bytes.ToLower(text)
+	sum := 0.0
+	count := 0
+	for i := 0; i+m.n <= len(lowered); i++ {
+		lp, ok := m.logProb[string(lowered[i:i+m.n])]
+		if !ok {
+			lp = m.floor
+		}
+		sum += lp
+		count++
+	}
+	return sum / float64(count)
+}
+
+// WordList scores a text by the fraction of its characters that belong
+// to known words. It needs contiguous text: it works for single-byte XOR
+// but not on the transposed columns of a repeating-key break.
+type WordList struct {
+	words map[string]bool
+}
+
+// EnglishWords are common English words, enough to tell prose from noise
+var EnglishWords = strings.Fields(`
+	the be to of and a in that have i it for not on with he as you do at
+	this but his by from they we say her she or an will my one all would
+	there their what so up out if about who get which go me when make can
+	like time no just him know take people into year your good some could
+	them see other than then now look only come its over think also back
+	after use two how our work first well way even new want because any
+	these give day most us is are was were been has had did said am`)
+
+// NewWordList builds a model that knows words
+func NewWordList(words []string) *WordList {
+	wl := &WordList{words: make(map[string]bool, len(words))}
+	for _, w := range words {
+		wl.words[strings.ToLower(w)] = true
+	}
+	return wl
+}
+
+// TrainWordList learns the words that appear at least minCount times in
+// corpus
+func TrainWordList(corpus io.Reader, minCount int) (*WordList, error) {
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(corpus)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		for _, w := range tokenize(scanner.Text()) {
+			counts[w]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("score: reading corpus: %w", err)
+	}
+
+	words := []string{}
+	for w, c := range counts {
+		if c >= minCount {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return nil, ErrEmptyCorpus
+	}
+	return NewWordList(words), nil
+}
+
+// tokenize lower cases s and splits it into runs of letters and
+// apostrophes
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+// Score returns the fraction of the text (in bytes) covered by known
+// words. Lower case letters and spaces add a small bonus to break the ties
+// between texts with the same words (a flipped case bit, or no words at
+// all).
+func (wl *WordList) Score(text []byte) float64 {
+	if len(text) == 0 {
+		return 0
+	}
+	known := 0
+	for _, w := range tokenize(string(text)) {
+		if wl.words[w] {
+			known += len(w)
+		}
+	}
+	plausible := 0
+	for _, b := range text {
+		if b == ' ' || (b >= 'a' && b <= 'z') {
+			plausible++
+		}
+	}
+	return (float64(known) + 0.1*float64(plausible)) / float64(len(text))
+}
@@ -0,0 +1,58 @@
+// Package score rates candidate plaintexts so the XOR breakers can pick
+// the key that produces the most language-like output.
+package score
+
+import (
+	"strings"
+)
+
+// Scorer rates how much text looks like the language it models. Higher
+// is better; only the ordering between scores of the same scorer is
+// meaningful.
+type Scorer interface {
+	Score(text []byte) float64
+}
+
+// Acceptor is implemented by scorers that decide themselves which texts
+// are plausible at all. The XOR breakers use it instead of their default
+// readability check.
+type Acceptor interface {
+	Accept(text []byte) bool
+}
+
+// Func adapts a plain function to the Scorer interface
+type Func func(text []byte) float64
+
+// Score calls f(text)
+func (f Func) Score(text []byte) float64 {
+	return f(text)
+}
+
+// Frequency adds up the frequency of every character of the text. It is
+// the simplest model: long texts made of common letters score high.
+type Frequency map[rune]float64
+
+// EnglishFrequencies holds common English letter frequencies
+var EnglishFrequencies = map[rune]float64{
+	' ': 0.13, 'e': 0.127, 't': 0.091, 'a': 0.082, 'o': 0.075,
+	'n': 0.067, 'i': 0.066, 's': 0.063, 'h': 0.061, 'r': 0.06,
+	'd': 0.043, 'l': 0.04, 'u': 0.028, 'c': 0.027, 'm': 0.024,
+	'f': 0.022, 'w': 0.02, 'y': 0.02, 'g': 0.02, 'p': 0.019,
+	'b': 0.015, 'v': 0.01, 'k': 0.008, 'x': 0.001, 'q': 0.001,
+	'j': 0.001, 'z': 0.001,
+}
+
+// English is the default scorer: the frequency sum over
+// EnglishFrequencies
+var English Scorer = Frequency(EnglishFrequencies)
+
+// Score returns the sum of the frequencies of the characters in text
+func (f Frequency) Score(text []byte) float64 {
+	score := 0.0
+	for _, r := range strings.ToLower(string(text)) {
+		if freq, exists := f[r]; exists {
+			score += freq
+		}
+	}
+	return score
+}
@@ -0,0 +1,183 @@
+package score
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drio/cryptopals/data"
+)
+
+// trainAll returns every model, the trainable ones trained on the
+// challenge 6 plaintext
+func trainAll(t *testing.T) map[string]Scorer {
+	t.Helper()
+	corpus := func() fs.File {
+		f, err := data.Open("set1/output6")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Close() })
+		return f
+	}
+
+	chi, err := TrainChiSquared(corpus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bigram, err := TrainNGram(corpus(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigram, err := TrainNGram(corpus(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words, err := TrainWordList(corpus(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]Scorer{
+		"frequency":         English,
+		"chi-squared":       NewChiSquared(EnglishFrequencies),
+		"chi-squared/train": chi,
+		"bigram":            bigram,
+		"trigram":           trigram,
+		"words":             NewWordList(EnglishWords),
+		"words/train":       words,
+	}
+}
+
+// beatsGarbled checks that text scores better than every single-byte XOR
+// of it
+func beatsGarbled(t *testing.T, name string, s Scorer, text string) {
+	t.Helper()
+	want := s.Score([]byte(text))
+	for k := 1; k < 256; k++ {
+		garbled := []byte(text)
+		for i := range garbled {
+			garbled[i] ^= byte(k)
+		}
+		if got := s.Score(garbled); got >= want {
+			t.Errorf("%s: %q XOR %d scores %f >= %f", name, text, k, got, want)
+			return
+		}
+	}
+}
+
+func TestScorersPreferPlainText(t *testing.T) {
+	scorers := trainAll(t)
+
+	t.Run("Prose", func(t *testing.T) {
+		for name, s := range scorers {
+			beatsGarbled(t, name, s, "Cooking MC's like a pound of bacon")
+		}
+	})
+
+	t.Run("Short and punctuation heavy", func(t *testing.T) {
+		for _, name := range []string{"chi-squared/train", "bigram", "trigram"} {
+			beatsGarbled(t, name, scorers[name], "Hi, Bob! It's me: (O.K.?) -- yes.")
+			beatsGarbled(t, name, scorers[name], "Meet at 10:30, room #42. Bring IDs!")
+		}
+	})
+}
+
+func TestTrainErrors(t *testing.T) {
+	if _, err := TrainChiSquared(strings.NewReader("")); !errors.Is(err, ErrEmptyCorpus) {
+		t.Errorf("expected ErrEmptyCorpus, got %v", err)
+	}
+	if _, err := TrainNGram(strings.NewReader("a"), 2); !errors.Is(err, ErrEmptyCorpus) {
+		t.Errorf("expected ErrEmptyCorpus, got %v", err)
+	}
+	if _, err := TrainNGram(strings.NewReader("abc"), 0); err == nil {
+		t.Errorf("expected an error for n=0")
+	}
+	if _, err := TrainWordList(strings.NewReader("  \n "), 1); !errors.Is(err, ErrEmptyCorpus) {
+		t.Errorf("expected ErrEmptyCorpus, got %v", err)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	s := Func(func(text []byte) float64 { return float64(len(text)) })
+	if s.Score([]byte("four")) != 4 {
+		t.Errorf("Func does not call the function")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	profiles := Profiles()
+	expected := []string{"code", "english", "french", "german", "spanish"}
+	if got := Names(profiles); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected profiles %v, got %v", expected, got)
+	}
+
+	samples := map[string]string{
+		"code":    "testdata/code.json",
+		"english": "../data/set1/output6.txt",
+		"french":  "testdata/french.txt",
+		"german":  "testdata/german.txt",
+		"spanish": "testdata/spanish.txt",
+	}
+	for lang, fn := range samples {
+		text, err := os.ReadFile(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p, _ := Detect(text, profiles); p.Name != lang {
+			t.Errorf("%s: detected %s instead of %s", fn, p.Name, lang)
+		}
+		for _, p := range profiles {
+			if !p.Accept(text) {
+				t.Errorf("%s rejects %s", p.Name, fn)
+			}
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		p, err := LoadProfile(strings.NewReader(`{"name": "tiny", "letters": {"a": 2, "ñ": 1, " ": 1}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Score([]byte("aña a")) <= p.Score([]byte("xyz q")) {
+			t.Errorf("profile prefers unknown letters")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		inputs := []string{
+			`not json`,
+			`{"name": "x", "letters": {"ab": 1}}`,
+			`{"name": "x", "letters": {}}`,
+		}
+		for _, in := range inputs {
+			if _, err := LoadProfile(strings.NewReader(in)); err == nil {
+				t.Errorf("LoadProfile(%q): expected an error", in)
+			}
+		}
+	})
+
+	t.Run("Builtin", func(t *testing.T) {
+		if _, err := Builtin("klingon"); err == nil {
+			t.Errorf("expected an error for an unknown profile")
+		}
+	})
+}
+
+func TestTrainProfile(t *testing.T) {
+	f, err := os.Open("testdata/german.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	p, err := TrainProfile("german/trained", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beatsGarbled(t, p.Name, p, "Die Kinder durften nicht mehr allein gehen.")
+}
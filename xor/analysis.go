@@ -0,0 +1,193 @@
+package xor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/drio/cryptopals/block"
+	"github.com/drio/cryptopals/content"
+	"github.com/drio/cryptopals/score"
+)
+
+// BlockHD holds the sum of the hamming distances between neighbouring
+// blocks and the number of pairs compared
+type BlockHD struct {
+	SumHD float64
+	Pairs float64
+}
+
+// Norm returns the mean Hamming distance per byte:
+//  1. average the HD across all block pairs (removes sample‑size bias)
+//  2. divide by the keysize (removes length bias)
+func (r BlockHD) Norm(keySize int) float64 {
+	return (r.SumHD / r.Pairs) / float64(keySize)
+}
+
+// ComputeBlockHD sums the hamming distances between neighbouring blocks
+// of keySize bytes in text; Pairs counts the pairs compared
+func ComputeBlockHD(text []byte, keySize int) BlockHD {
+	sumHD := 0
+	pairs := 0
+	for i := 0; i+2*keySize <= len(text); i += keySize {
+		sumHD += hammingBytes(text[i:i+keySize], text[i+keySize:i+2*keySize])
+		pairs++
+	}
+	return BlockHD{
+		float64(sumHD),
+		float64(pairs),
+	}
+}
+
+// Hamming returns the number of differing bits between a and b
+func Hamming(a, b string) (int, error) {
+	return HammingBytes([]byte(a), []byte(b))
+}
+
+// HammingBytes returns the number of differing bits between a and b
+func HammingBytes(a, b []byte) (int, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("%w: %d != %d", ErrLengthMismatch, len(a), len(b))
+	}
+	return hammingBytes(a, b), nil
+}
+
+// hammingBytes expects len(ba) == len(bb). It counts the bits a word at a
+// time.
+func hammingBytes(ba, bb []byte) int {
+	n := len(ba)
+	ba, bb = ba[:n], bb[:n]
+	distance := 0
+	i := 0
+	for ; i+wordSize <= n; i += wordSize {
+		distance += bits.OnesCount64(binary.NativeEndian.Uint64(ba[i:]) ^ binary.NativeEndian.Uint64(bb[i:]))
+	}
+	for ; i < n; i++ {
+		distance += bits.OnesCount8(ba[i] ^ bb[i])
+	}
+	return distance
+}
+
+// ScoreText scores s based on English character frequency
+func ScoreText(s string) float64 {
+	return score.English.Score([]byte(s))
+}
+
+// IsReadableText reports whether s looks like printable prose
+//
+// Deprecated: it rejects valid UTF-8, JSON, base64 and code. Use
+// content.Classify.
+func IsReadableText(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	printableCount := 0
+	spaceCount := 0
+	letterCount := 0
+
+	for i := range s {
+		// Printable ASCII
+		if s[i] >= 32 && s[i] <= 126 {
+			printableCount++
+
+			// Count spaces
+			if s[i] == 32 {
+				spaceCount++
+			}
+
+			// Count letters
+			if (s[i] >= 65 && s[i] <= 90) || (s[i] >= 97 && s[i] <= 122) {
+				letterCount++
+			}
+		}
+	}
+
+	// Criteria for readable text:
+	// 1. At least 90% printable characters
+	// 2. At least one space (to suggest multiple words)
+	// 3. At least some letters
+	return float64(printableCount)/float64(len(s)) > 0.9 &&
+		spaceCount > 0 &&
+		float64(letterCount)/float64(len(s)) > 0.1
+}
+
+// ScoreRepeatKey scores the plaintext we get when XORing cipherText with
+// key. Plaintexts content.Classify takes for noise score 0.
+func ScoreRepeatKey(cipherText []byte, key []byte) float64 {
+	plainBytes, err := Bytes(cipherText, key)
+	if err != nil {
+		return 0.0
+	}
+	if s := scorePlainText(plainBytes, score.English); !math.IsInf(s, -1) {
+		return s
+	}
+	return 0.0
+}
+
+// scorePlainText scores a candidate plaintext with s, -Inf when it is not
+// readable. Scorers that implement score.Acceptor decide what readable
+// means, for the rest content.Classify has to find it plausible.
+func scorePlainText(plainBytes []byte, s score.Scorer) float64 {
+	readable := false
+	if a, ok := s.(score.Acceptor); ok {
+		readable = a.Accept(plainBytes)
+	} else {
+		readable = content.Classify(plainBytes).Plausible()
+	}
+
+	if readable {
+		return s.Score(plainBytes)
+	}
+	return math.Inf(-1)
+}
+
+// scorerOrDefault returns s, or the English scorer when s is nil
+func scorerOrDefault(s score.Scorer) score.Scorer {
+	if s == nil {
+		return score.English
+	}
+	return s
+}
+
+// FindKeyByTransposing finds the actual key given a ciphertext and a key
+// size. It fails with ErrNoKey when the ciphertext is shorter than the key.
+func FindKeyByTransposing(cipherBytes []byte, kSize int) (string, error) {
+	return FindKeyByTransposingWith(cipherBytes, kSize, score.English)
+}
+
+// FindKeyByTransposingWith is like FindKeyByTransposing but rates the
+// candidate key bytes with s
+func FindKeyByTransposingWith(cipherBytes []byte, kSize int, s score.Scorer) (string, error) {
+	columns, err := FindKeyCandidates(cipherBytes, kSize, s, 1)
+	if err != nil {
+		return "", err
+	}
+
+	r := make([]byte, len(columns))
+	for i, c := range columns {
+		r[i] = c.Best().Key
+	}
+	return string(r), nil
+}
+
+// FindKeyCandidates breaks every column of the transposed cipherBytes
+// and returns the n best key bytes for each position of the key. Use it
+// to show the alternatives of the ambiguous positions.
+func FindKeyCandidates(cipherBytes []byte, kSize int, s score.Scorer, n int) ([]SingleByteResult, error) {
+	if kSize < 1 {
+		return nil, fmt.Errorf("%w: key size %d", ErrEmptyKey, kSize)
+	}
+	if len(cipherBytes) < kSize {
+		return nil, fmt.Errorf("%w: ciphertext shorter than key size %d", ErrNoKey, kSize)
+	}
+	blocks := block.Split(cipherBytes, kSize)
+
+	tBlocks := block.Transpose(blocks, kSize)
+	columns := make([]SingleByteResult, len(tBlocks))
+	for i, value := range tBlocks {
+		columns[i] = BreakSingleByteTop(value, s, n)
+	}
+	return columns, nil
+}
@@ -0,0 +1,113 @@
+package xor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// wordSize is how many bytes the XOR loops process at once
+const wordSize = 8
+
+// tileSize bounds the key stream the repeating-key XOR builds on the
+// stack: short keys are repeated to fill it so the inner loop runs on
+// words whatever the key length
+const tileSize = 512
+
+// xorWords sets dst[i] = a[i] ^ b[i] for every i < len(dst), a word at a
+// time. a and b must be at least as long as dst; any of them may alias.
+func xorWords(dst, a, b []byte) {
+	n := len(dst)
+	a, b = a[:n], b[:n]
+	i := 0
+	for ; i+4*wordSize <= n; i += 4 * wordSize {
+		d, x, y := dst[i:i+4*wordSize], a[i:i+4*wordSize], b[i:i+4*wordSize]
+		binary.NativeEndian.PutUint64(d[0:], binary.NativeEndian.Uint64(x[0:])^binary.NativeEndian.Uint64(y[0:]))
+		binary.NativeEndian.PutUint64(d[8:], binary.NativeEndian.Uint64(x[8:])^binary.NativeEndian.Uint64(y[8:]))
+		binary.NativeEndian.PutUint64(d[16:], binary.NativeEndian.Uint64(x[16:])^binary.NativeEndian.Uint64(y[16:]))
+		binary.NativeEndian.PutUint64(d[24:], binary.NativeEndian.Uint64(x[24:])^binary.NativeEndian.Uint64(y[24:]))
+	}
+	for ; i+wordSize <= n; i += wordSize {
+		binary.NativeEndian.PutUint64(dst[i:], binary.NativeEndian.Uint64(a[i:])^binary.NativeEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+// fillTile repeats key over buf and returns the longest prefix holding a
+// whole number of copies. Keys longer than buf are returned as a copy, so
+// the tile never aliases the caller's key.
+func fillTile(buf []byte, key []byte) []byte {
+	if len(key) > len(buf) {
+		return bytes.Clone(key)
+	}
+	t := buf[:len(buf)/len(key)*len(key)]
+	for i := 0; i < len(t); i += copy(t[i:], key) {
+	}
+	return t
+}
+
+// repeatWords sets dst[i] = src[i] ^ t[(phase+i) % len(t)]. dst must be
+// at least as long as src and may alias it.
+func repeatWords(dst, src, t []byte, phase int) {
+	for len(src) > 0 {
+		chunk := t[phase:]
+		n := min(len(src), len(chunk))
+		xorWords(dst[:n], src, chunk)
+		dst, src = dst[n:], src[n:]
+		phase = 0
+	}
+}
+
+// repeat sets dst[i] = src[i] ^ key[(offset+i) % len(key)]
+func repeat(dst, src, key []byte, offset int) {
+	var buf [tileSize]byte
+	repeatWords(dst, src, fillTile(buf[:], key), offset%len(key))
+}
+
+// BytesInPlace XORs src into dst, which must have the same length
+func BytesInPlace(dst, src []byte) error {
+	if len(dst) != len(src) {
+		return fmt.Errorf("%w: %d != %d", ErrLengthMismatch, len(dst), len(src))
+	}
+	xorWords(dst, dst, src)
+	return nil
+}
+
+// RepeatInPlace XORs buf with the repeating key
+func RepeatInPlace(buf, key []byte) error {
+	if len(key) == 0 {
+		return ErrEmptyKey
+	}
+	repeat(buf, buf, key, 0)
+	return nil
+}
+
+// Stream XORs a stream of data with a repeating key, picking up the key
+// where the previous call left it. It implements cipher.Stream, so
+// cipher.StreamReader and cipher.StreamWriter turn it into an io.Reader
+// or io.Writer.
+type Stream struct {
+	tile   []byte // the key repeated
+	keyLen int
+	pos    int // position in the key of the next byte
+}
+
+// NewStream returns a Stream for key
+func NewStream(key []byte) (*Stream, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	return &Stream{fillTile(make([]byte, tileSize), key), len(key), 0}, nil
+}
+
+// XORKeyStream XORs src with the key stream into dst. dst must be at
+// least as long as src and may alias it.
+func (s *Stream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("xor: output smaller than input")
+	}
+	repeatWords(dst, src, s.tile, s.pos)
+	s.pos = (s.pos + len(src)) % s.keyLen
+}
@@ -0,0 +1,196 @@
+package xor
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// naiveRepeat is the byte at a time reference for the word loops
+func naiveRepeat(src, key []byte, offset int) []byte {
+	dst := make([]byte, len(src))
+	for i := range src {
+		dst[i] = src[i] ^ key[(offset+i)%len(key)]
+	}
+	return dst
+}
+
+func TestEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+
+	t.Run("Repeat matches the reference", func(t *testing.T) {
+		for _, n := range []int{0, 1, 7, 8, 9, 31, 32, 33, 511, 512, 513, 4099} {
+			for _, kl := range []int{1, 3, 8, 29, 511, 512, 513, 1000} {
+				src, key := random(n), random(kl)
+				if got := MustRepeat(src, key); !bytes.Equal(got, naiveRepeat(src, key, 0)) {
+					t.Fatalf("len %d, key %d: wrong output", n, kl)
+				}
+
+				buf := bytes.Clone(src)
+				if err := RepeatInPlace(buf, key); err != nil || !bytes.Equal(buf, naiveRepeat(src, key, 0)) {
+					t.Fatalf("len %d, key %d: wrong in place output (%v)", n, kl, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Bytes matches the reference", func(t *testing.T) {
+		for _, n := range []int{0, 1, 8, 33, 1000} {
+			a, b := random(n), random(n)
+			got := MustBytes(a, b)
+			for i := range got {
+				if got[i] != a[i]^b[i] {
+					t.Fatalf("len %d: wrong output at %d", n, i)
+				}
+			}
+			if err := BytesInPlace(got, b); err != nil || !bytes.Equal(got, a) {
+				t.Fatalf("len %d: wrong in place output (%v)", n, err)
+			}
+		}
+		if err := BytesInPlace(make([]byte, 2), make([]byte, 3)); err == nil {
+			t.Errorf("expected a length mismatch")
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		src, key := random(3000), []byte("Terminator X: Bring the noise")
+		s, err := NewStream(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// odd sized writes keep the key position
+		dst := make([]byte, 0, len(src))
+		for rest := src; len(rest) > 0; {
+			n := min(len(rest), 1+rng.Intn(100))
+			out := make([]byte, n)
+			s.XORKeyStream(out, rest[:n])
+			dst, rest = append(dst, out...), rest[n:]
+		}
+		if !bytes.Equal(dst, naiveRepeat(src, key, 0)) {
+			t.Errorf("wrong stream output")
+		}
+
+		s, _ = NewStream(key)
+		r := cipher.StreamReader{S: s, R: bytes.NewReader(src)}
+		got, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, naiveRepeat(src, key, 0)) {
+			t.Errorf("wrong stream reader output (%v)", err)
+		}
+
+		if _, err := NewStream(nil); err != ErrEmptyKey {
+			t.Errorf("expected ErrEmptyKey, got %v", err)
+		}
+	})
+
+	t.Run("Stream keeps its own key", func(t *testing.T) {
+		// keys longer than a tile are not repeated into one
+		for _, kl := range []int{29, tileSize, tileSize + 1, 1000} {
+			src, key := random(2000), random(kl)
+			expected := naiveRepeat(src, key, 0)
+			s, err := NewStream(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range key {
+				key[i] ^= 0xff
+			}
+			dst := make([]byte, len(src))
+			s.XORKeyStream(dst, src)
+			if !bytes.Equal(dst, expected) {
+				t.Errorf("key %d: changing the key changed the stream", kl)
+			}
+		}
+	})
+
+	t.Run("RepeatHex works on bytes", func(t *testing.T) {
+		got, err := RepeatHex("niño", "K")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := hex.EncodeToString(naiveRepeat([]byte("niño"), []byte("K"), 0)); got != expected {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("No allocations", func(t *testing.T) {
+		buf, key := random(4096), random(29)
+		allocs := testing.AllocsPerRun(100, func() {
+			RepeatInPlace(buf, key)
+			BytesInPlace(buf, buf)
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %.1f", allocs)
+		}
+	})
+}
+
+// repeatHexString is the old RepeatHex: it formatted every rune as hex
+// and decoded it again to XOR it with the key
+func repeatHexString(plainText, rkey string) (string, error) {
+	skey := []string{}
+	for _, b := range rkey {
+		skey = append(skey, fmt.Sprintf("%02x", b))
+	}
+
+	xorHexOutput := []string{}
+	for i, b := range plainText {
+		x, err := Hex(fmt.Sprintf("%02x", b), skey[i%len(rkey)])
+		if err != nil {
+			return "", err
+		}
+		xorHexOutput = append(xorHexOutput, x)
+	}
+	return strings.Join(xorHexOutput, ""), nil
+}
+
+func BenchmarkRepeat(b *testing.B) {
+	key := []byte("ICE")
+	for _, size := range []int{1 << 10, 1 << 20} {
+		plainText := bytes.Repeat([]byte("Burning 'em, if you ain't quick and nimble\n"), size/43+1)[:size]
+
+		b.Run(fmt.Sprintf("HexString/%d", size), func(b *testing.B) {
+			if size > 1<<10 {
+				b.Skip("too slow")
+			}
+			b.SetBytes(int64(size))
+			for b.Loop() {
+				repeatHexString(string(plainText), string(key))
+			}
+		})
+		b.Run(fmt.Sprintf("Naive/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for b.Loop() {
+				naiveRepeat(plainText, key, 0)
+			}
+		})
+		b.Run(fmt.Sprintf("RepeatHex/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for b.Loop() {
+				RepeatHex(string(plainText), string(key))
+			}
+		})
+		b.Run(fmt.Sprintf("Repeat/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for b.Loop() {
+				Repeat(plainText, key)
+			}
+		})
+		b.Run(fmt.Sprintf("RepeatInPlace/%d", size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for b.Loop() {
+				RepeatInPlace(plainText, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package xor
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// KeySizeEstimator ranks the key sizes in [minSize, maxSize] that could
+// have encrypted text with repeating-key XOR, most likely first
+type KeySizeEstimator func(text []byte, minSize, maxSize int) []KeySize
+
+// kasiskiSeq is the length of the repeated sequences Kasiski looks for
+const kasiskiSeq = 3
+
+// RankKeySizesKasiski ranks the key sizes with the Kasiski examination:
+// the same plaintext under the same part of the key repeats in the
+// ciphertext, so the distances between repeated sequences are multiples of
+// the key size. Score is the share of distances the size divides, minus
+// the share it would divide by chance.
+func RankKeySizesKasiski(text []byte, minSize, maxSize int) []KeySize {
+	last := map[uint32]int{}
+	// distances[d] counts the repeats d bytes apart
+	distances := make([]int, len(text))
+	total := 0
+	for i := 0; i+kasiskiSeq <= len(text); i++ {
+		seq := uint32(text[i])<<16 | uint32(text[i+1])<<8 | uint32(text[i+2])
+		if j, ok := last[seq]; ok {
+			distances[i-j]++
+			total++
+		}
+		last[seq] = i
+	}
+
+	ranked := []KeySize{}
+	if total == 0 {
+		return ranked
+	}
+	for ks := max(minSize, 1); ks <= maxSize; ks++ {
+		divisible := 0
+		for d := ks; d < len(distances); d += ks {
+			divisible += distances[d]
+		}
+		share := float64(divisible) / float64(total)
+		ranked = append(ranked, KeySize{ks, share - 1/float64(ks)})
+	}
+	return sortKeySizes(ranked, true)
+}
+
+// RankKeySizesIC ranks the key sizes with Friedman's index of
+// coincidence: every column of the transposed text is encrypted with a
+// single byte, which keeps the coincidences of the plaintext, while the
+// columns of a wrong size look random. Score is the mean index of
+// coincidence of the columns.
+func RankKeySizesIC(text []byte, minSize, maxSize int) []KeySize {
+	text = text[:min(len(text), icSample)]
+	ranked := []KeySize{}
+	for ks := max(minSize, 1); ks <= maxSize; ks++ {
+		if len(text) < 2*ks {
+			continue
+		}
+		// the byte counts of every column
+		counts := make([][256]int, ks)
+		col := 0
+		for _, c := range text {
+			counts[col][c]++
+			if col++; col == ks {
+				col = 0
+			}
+		}
+		sum := 0.0
+		for col := range counts {
+			n := (len(text) - col + ks - 1) / ks
+			sum += coincidence(&counts[col], n)
+		}
+		ranked = append(ranked, KeySize{ks, sum / float64(ks)})
+	}
+	return sortKeySizes(preferDivisors(ranked, true), true)
+}
+
+// icSample is how much of the text RankKeySizesIC looks at: the
+// coincidences settle long before a megabyte
+const icSample = 1 << 16
+
+// indexOfCoincidence returns the probability that two bytes drawn from b
+// are equal
+func indexOfCoincidence(b []byte) float64 {
+	if len(b) < 2 {
+		return 0
+	}
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+	return coincidence(&counts, len(b))
+}
+
+// coincidence returns the index of coincidence of n bytes given their
+// counts
+func coincidence(counts *[256]int, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	sum := 0
+	for _, c := range counts {
+		sum += c * (c - 1)
+	}
+	return float64(sum) / float64(n*(n-1))
+}
+
+// RankKeySizesAutocorrelation ranks the key sizes by comparing text with
+// itself shifted by the size: when the shift is a multiple of the key,
+// two bytes match whenever their plaintexts do. Score is the share of
+// matching bytes.
+func RankKeySizesAutocorrelation(text []byte, minSize, maxSize int) []KeySize {
+	ranked := []KeySize{}
+	for ks := max(minSize, 1); ks <= maxSize; ks++ {
+		if len(text) < 2*ks {
+			continue
+		}
+		matches := 0
+		for i := ks; i < len(text); i++ {
+			if text[i] == text[i-ks] {
+				matches++
+			}
+		}
+		ranked = append(ranked, KeySize{ks, float64(matches) / float64(len(text)-ks)})
+	}
+	return sortKeySizes(preferDivisors(ranked, true), true)
+}
+
+// divisorTolerance is how close to a multiple a divisor has to score for
+// preferDivisors to take it
+const divisorTolerance = 0.75
+
+// preferDivisors moves the multiples of a key size below it. Estimators
+// that score higher-is-better rate the multiples of the key as well as
+// the key itself, and a multiple wins on noise about as often as not, so a
+// size scoring close to one of its divisors gets a score just below it.
+// When desc is false lower scores are better.
+func preferDivisors(ranked []KeySize, desc bool) []KeySize {
+	scores := map[int]float64{}
+	for _, ks := range ranked {
+		scores[ks.Size] = ks.Score
+	}
+	for i, ks := range ranked {
+		for d := 1; d < ks.Size; d++ {
+			sd, ok := scores[d]
+			if !ok || ks.Size%d != 0 {
+				continue
+			}
+			if desc && sd > 0 && sd >= divisorTolerance*ks.Score {
+				ranked[i].Score = math.Nextafter(sd, math.Inf(-1))
+				break
+			}
+			if !desc && sd*divisorTolerance <= ks.Score {
+				ranked[i].Score = math.Nextafter(sd, math.Inf(1))
+				break
+			}
+		}
+	}
+	return ranked
+}
+
+// rankKeySizesHamming is RankKeySizesAllPairs with the multiples of a
+// size moved below it
+func rankKeySizesHamming(text []byte, minSize, maxSize int) []KeySize {
+	return sortKeySizes(preferDivisors(RankKeySizesAllPairs(text, minSize, maxSize), false), false)
+}
+
+// DefaultEstimators are the estimators RankKeySizesCombined uses when
+// given none. Autocorrelation is left out: on short ciphertexts it picks
+// multiples of the key often enough to outvote the others.
+var DefaultEstimators = []KeySizeEstimator{
+	rankKeySizesHamming,
+	RankKeySizesKasiski,
+	RankKeySizesIC,
+}
+
+// RankKeySizesCombined ranks the key sizes with every estimator and
+// merges the rankings. Score is the mean position of the size across the
+// rankings (0 is first): the lower, the more likely. The estimators fail
+// in different ways, so the sizes they agree on come first.
+func RankKeySizesCombined(text []byte, minSize, maxSize int, estimators ...KeySizeEstimator) []KeySize {
+	if len(estimators) == 0 {
+		estimators = DefaultEstimators
+	}
+
+	rankings := make([]map[int]int, len(estimators))
+	for i, estimate := range estimators {
+		rankings[i] = map[int]int{}
+		for pos, ks := range estimate(text, minSize, maxSize) {
+			rankings[i][ks.Size] = pos
+		}
+	}
+
+	combined := []KeySize{}
+	for size := max(minSize, 1); size <= maxSize; size++ {
+		sum, rated := 0, false
+		for _, r := range rankings {
+			pos, ok := r[size]
+			if !ok {
+				// sizes an estimator could not rate come last
+				pos = len(r)
+			}
+			sum += pos
+			rated = rated || ok
+		}
+		if rated {
+			combined = append(combined, KeySize{size, float64(sum) / float64(len(rankings))})
+		}
+	}
+	return sortKeySizes(combined, false)
+}
+
+// rankKeySizesCombined is RankKeySizesCombined with DefaultEstimators
+func rankKeySizesCombined(text []byte, minSize, maxSize int) []KeySize {
+	return RankKeySizesCombined(text, minSize, maxSize)
+}
+
+// sortKeySizes sorts ranked by score, highest first when desc; equal
+// scores keep their order
+func sortKeySizes(ranked []KeySize, desc bool) []KeySize {
+	slices.SortStableFunc(ranked, func(a, b KeySize) int {
+		if desc {
+			return cmp.Compare(b.Score, a.Score)
+		}
+		return cmp.Compare(a.Score, b.Score)
+	})
+	return ranked
+}
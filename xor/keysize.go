@@ -0,0 +1,250 @@
+package xor
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// KeySize is a candidate key size for repeating-key XOR. What Score
+// measures depends on the estimator that ranked it.
+type KeySize struct {
+	Size  int
+	Score float64
+}
+
+// RankKeySizes scores every key size in [minSize, maxSize] by the
+// normalized Hamming distance between blocks of that size (the lower, the
+// more likely) and returns the candidates ordered from most to least
+// likely. Sizes with fewer than two blocks to compare are skipped.
+func RankKeySizes(text []byte, minSize, maxSize int) []KeySize {
+	ranked := []KeySize{}
+	for ks := max(minSize, 1); ks <= maxSize; ks++ {
+		r := ComputeBlockHD(text, ks)
+		if r.Pairs < 1 {
+			continue
+		}
+		ranked = append(ranked, KeySize{ks, r.Norm(ks)})
+	}
+
+	slices.SortStableFunc(ranked, func(a, b KeySize) int {
+		switch {
+		case a.Score < b.Score:
+			return -1
+		case a.Score > b.Score:
+			return 1
+		}
+		return 0
+	})
+	return ranked
+}
+
+// DefaultMaxPairs is how many block pairs per key size
+// RankKeySizesAllPairs compares at most
+const DefaultMaxPairs = 2000
+
+// RankKeySizesAllPairs is like RankKeySizes but averages the Hamming
+// distance over every pair of blocks, not only the neighbouring ones,
+// which steadies the estimate on short ciphertexts. Past DefaultMaxPairs
+// pairs it compares a sample, so megabyte ciphertexts stay cheap.
+func RankKeySizesAllPairs(text []byte, minSize, maxSize int) []KeySize {
+	return PairwiseHamming(DefaultMaxPairs)(text, minSize, maxSize)
+}
+
+// PairwiseHamming returns an estimator that scores a key size with the
+// mean normalized Hamming distance between pairs of blocks of that size:
+// all of them, or maxPairs picked at random when there are more (and
+// maxPairs > 0). The sample is seeded, so the ranking is reproducible.
+func PairwiseHamming(maxPairs int) KeySizeEstimator {
+	return func(text []byte, minSize, maxSize int) []KeySize {
+		ranked := []KeySize{}
+		for ks := max(minSize, 1); ks <= maxSize; ks++ {
+			blocks := len(text) / ks
+			if blocks < 2 {
+				continue
+			}
+			block := func(i int) []byte { return text[i*ks : (i+1)*ks] }
+
+			sum, pairs := 0, 0
+			if total := blocks * (blocks - 1) / 2; maxPairs <= 0 || total <= maxPairs {
+				for i := range blocks {
+					for j := i + 1; j < blocks; j++ {
+						sum += hammingBytes(block(i), block(j))
+					}
+				}
+				pairs = total
+			} else {
+				rng := rand.New(rand.NewPCG(uint64(ks), uint64(len(text))))
+				for range maxPairs {
+					i := rng.IntN(blocks)
+					j := rng.IntN(blocks - 1)
+					if j >= i {
+						j++ // any block but i
+					}
+					sum += hammingBytes(block(i), block(j))
+				}
+				pairs = maxPairs
+			}
+			ranked = append(ranked, KeySize{ks, float64(sum) / float64(pairs) / float64(ks)})
+		}
+		return sortKeySizes(ranked, false)
+	}
+}
+
+// BreakOptions tunes BreakRepeatingKeyXOR
+type BreakOptions struct {
+	MinKeySize int // smallest key size to consider
+	MaxKeySize int // largest key size to consider
+	Candidates int // how many of the top ranked key sizes to try
+
+	// Estimator ranks the key sizes (RankKeySizesCombined when nil)
+	Estimator KeySizeEstimator
+
+	// Scorer rates the candidate plaintexts (score.English when nil)
+	Scorer score.Scorer
+
+	// Profiles, when set, replace Scorer: the break is tried with every
+	// profile and the plaintext that fits its language best wins
+	Profiles []*score.Profile
+}
+
+// DefaultBreakOptions are the options BreakRepeatingKeyXOR uses
+var DefaultBreakOptions = BreakOptions{
+	MinKeySize: 2,
+	MaxKeySize: 40,
+	Candidates: 5,
+}
+
+// BreakResult is the outcome of breaking a repeating-key XOR ciphertext
+type BreakResult struct {
+	Key       []byte
+	PlainText []byte
+	Score     float64 // score of the plaintext under the Scorer used
+	Language  string  // name of the winning profile, when using Profiles
+}
+
+// BreakRepeatingKeyXOR recovers the key and plaintext of cipherText using
+// DefaultBreakOptions
+func BreakRepeatingKeyXOR(cipherText []byte) (BreakResult, error) {
+	return BreakRepeatingKeyXORWith(cipherText, DefaultBreakOptions)
+}
+
+// BreakRepeatingKeyXORWith ranks the key sizes with opts.Estimator, tries the best
+// opts.Candidates of them with FindKeyByTransposing and returns the key
+// whose plaintext scores best.
+func BreakRepeatingKeyXORWith(cipherText []byte, opts BreakOptions) (BreakResult, error) {
+	return breakSamples([]sample{{0, cipherText}}, opts)
+}
+
+// breakSamples breaks the repeating-key XOR of a ciphertext given some
+// samples of it. The key sizes are ranked on the first sample.
+func breakSamples(samples []sample, opts BreakOptions) (BreakResult, error) {
+	estimate := opts.Estimator
+	if estimate == nil {
+		estimate = rankKeySizesCombined
+	}
+	ranked := estimate(samples[0].data, opts.MinKeySize, opts.MaxKeySize)
+	if len(ranked) == 0 {
+		return BreakResult{}, fmt.Errorf("%w: ciphertext too short for key sizes [%d, %d]",
+			ErrNoKey, opts.MinKeySize, opts.MaxKeySize)
+	}
+
+	candidates := ranked[:min(opts.Candidates, len(ranked))]
+	if len(opts.Profiles) == 0 {
+		return breakRanked(samples, candidates, scorerOrDefault(opts.Scorer), nil)
+	}
+
+	best := BreakResult{Score: math.Inf(-1)}
+	for _, p := range opts.Profiles {
+		r, err := breakRanked(samples, candidates, p, p.Bytes())
+		if err == nil && r.Score > best.Score {
+			best = r
+			best.Language = p.Name
+		}
+	}
+	if best.Key == nil {
+		return BreakResult{}, ErrNoKey
+	}
+	return best, nil
+}
+
+// multipleMargin is how much better, relative to its score, a key size
+// has to do than a divisor of it to win. A multiple of the real size
+// fits every column on its own, so it scores at least as well as the real
+// size even when its extra freedom only garbles the plaintext: on the
+// challenge 6 text a 40 byte key beats the real 10 byte one by 0.2%.
+const multipleMargin = 0.01
+
+// breakRanked tries every key size in candidates on the samples and
+// returns the key whose plaintext scores best under s, preferring a
+// smaller size to a multiple of it unless the multiple wins by
+// multipleMargin. The key bytes are searched with columns, or s when nil.
+// PlainText holds the decrypted samples, one after the other.
+func breakRanked(samples []sample, candidates []KeySize, s, columns score.Scorer) (BreakResult, error) {
+	if columns == nil {
+		columns = s
+	}
+
+	var results []BreakResult
+	best := -1
+	for _, ks := range candidates {
+		key := make([]byte, ks.Size)
+		for i, c := range sampleColumns(samples, ks.Size) {
+			if len(c) == 0 {
+				key = nil
+				break
+			}
+			key[i] = BreakSingleByteTop(c, columns, 1).Best().Key
+		}
+		if key == nil {
+			continue
+		}
+
+		plainText := []byte{}
+		for _, smp := range samples {
+			n := len(plainText)
+			plainText = append(plainText, smp.data...)
+			repeat(plainText[n:], smp.data, key, int(smp.offset%int64(len(key))))
+		}
+		sc := scorePlainText(plainText, s)
+		if math.IsInf(sc, -1) {
+			continue
+		}
+		results = append(results, BreakResult{Key: key, PlainText: plainText, Score: sc})
+		if best < 0 || sc > results[best].Score {
+			best = len(results) - 1
+		}
+	}
+	if best < 0 {
+		return BreakResult{}, ErrNoKey
+	}
+
+	// fall back to the smallest divisor the winner doesn't clearly beat
+	winner := results[best]
+	for i, r := range results {
+		if len(r.Key) < len(results[best].Key) && len(winner.Key)%len(r.Key) == 0 &&
+			winner.Score <= r.Score+multipleMargin*math.Abs(r.Score) {
+			best = i
+		}
+	}
+
+	result := results[best]
+	result.Key = minimalPeriod(result.Key)
+	return result, nil
+}
+
+// minimalPeriod returns the shortest prefix of key that, repeated,
+// produces key. A key found for a multiple of the real size is the real
+// key repeated.
+func minimalPeriod(key []byte) []byte {
+	for p := 1; p < len(key); p++ {
+		if len(key)%p == 0 && bytes.Equal(key[p:], key[:len(key)-p]) {
+			return key[:p]
+		}
+	}
+	return key
+}
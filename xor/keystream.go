@@ -0,0 +1,48 @@
+package xor
+
+import (
+	"fmt"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// KeystreamResult is the outcome of breaking ciphertexts that share a
+// keystream
+type KeystreamResult struct {
+	Keystream  []byte   // as long as the longest ciphertext
+	PlainTexts [][]byte // in the order of the ciphertexts
+}
+
+// BreakKeystream recovers a keystream XORed, from their first byte, with
+// every ciphertext in cipherTexts: a reused one-time pad or stream cipher
+// nonce, or messages under the same repeating key. Byte i of the
+// keystream is broken as a single-byte XOR over byte i of every
+// ciphertext, rated with s (score.English when nil), so the more
+// ciphertexts reach a position the likelier its byte is right.
+func BreakKeystream(cipherTexts [][]byte, s score.Scorer) (KeystreamResult, error) {
+	var columns [][]byte
+	for _, ct := range cipherTexts {
+		for i, b := range ct {
+			if i == len(columns) {
+				columns = append(columns, nil)
+			}
+			columns[i] = append(columns[i], b)
+		}
+	}
+	if len(columns) == 0 {
+		return KeystreamResult{}, fmt.Errorf("%w: no ciphertext", ErrNoKey)
+	}
+
+	s = scorerOrDefault(s)
+	keystream := make([]byte, len(columns))
+	for i, c := range columns {
+		keystream[i] = BreakSingleByteTop(c, s, 1).Best().Key
+	}
+
+	plainTexts := make([][]byte, len(cipherTexts))
+	for i, ct := range cipherTexts {
+		plainTexts[i] = make([]byte, len(ct))
+		xorWords(plainTexts[i], ct, keystream[:len(ct)])
+	}
+	return KeystreamResult{keystream, plainTexts}, nil
+}
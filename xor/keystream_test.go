@@ -0,0 +1,50 @@
+package xor
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/drio/cryptopals/data"
+)
+
+func TestBreakKeystream(t *testing.T) {
+	t.Run("Reused pad", func(t *testing.T) {
+		lines := strings.Split(string(data.MustReadFile("set1/output6")), "\n")[:40]
+		pad := make([]byte, 64)
+		rand.New(rand.NewSource(1)).Read(pad)
+
+		cipherTexts := make([][]byte, len(lines))
+		for i, l := range lines {
+			cipherTexts[i] = MustBytes([]byte(l), pad[:len(l)])
+		}
+		r, err := BreakKeystream(cipherTexts, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the first 20 bytes are 40 ciphertexts deep. The first two, capitals
+		// and the letters after them, don't look like running text.
+		right := 0
+		for i := range 20 {
+			if r.Keystream[i] == pad[i] {
+				right++
+			}
+		}
+		if right < 18 {
+			t.Errorf("only %d of the first 20 keystream bytes right: %x, want %x", right, r.Keystream[:20], pad[:20])
+		}
+		for i, l := range lines {
+			if len(r.PlainTexts[i]) != len(l) {
+				t.Errorf("line %d: expected %d bytes, got %d", i, len(l), len(r.PlainTexts[i]))
+			}
+		}
+	})
+
+	t.Run("Nothing to break", func(t *testing.T) {
+		if _, err := BreakKeystream(nil, nil); !errors.Is(err, ErrNoKey) {
+			t.Errorf("expected ErrNoKey, got %v", err)
+		}
+	})
+}
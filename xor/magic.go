@@ -0,0 +1,286 @@
+package xor
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"debug/elf"
+	"fmt"
+	"image/png"
+	"io"
+	"slices"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// Crib is plaintext known to be at Offset. Negative offsets count from
+// the end of the file.
+type Crib struct {
+	Offset int
+	Bytes  []byte
+}
+
+// FileType is a file format the known-plaintext attack recognises: the
+// bytes every file of the type holds, and a check the whole decrypted
+// file has to pass
+type FileType struct {
+	Name  string
+	Cribs []Crib
+	// Variants are sets of cribs of which one holds besides Cribs, like
+	// the line endings a trailer may have, tried in turn
+	Variants [][]Crib
+	// Check validates a decryption beyond the cribs (nil accepts it)
+	Check func(plainText []byte) bool
+}
+
+// FileTypes are the formats BreakKnownPlaintext tries by default
+var FileTypes = []FileType{
+	{
+		Name: "png",
+		Cribs: []Crib{
+			{0, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")},
+			{-12, []byte("\x00\x00\x00\x00IEND\xae\x42\x60\x82")},
+		},
+		Check: func(pt []byte) bool {
+			_, err := png.DecodeConfig(bytes.NewReader(pt))
+			return err == nil
+		},
+	},
+	{
+		Name: "zip",
+		Cribs: []Crib{
+			{0, []byte("PK\x03\x04")},
+			// end of central directory, on disk 0, without a comment
+			{-22, []byte("PK\x05\x06\x00\x00\x00\x00")},
+			{-2, []byte("\x00\x00")},
+		},
+		Check: func(pt []byte) bool {
+			r, err := zip.NewReader(bytes.NewReader(pt), int64(len(pt)))
+			if err != nil || len(r.File) == 0 {
+				return false
+			}
+			f, err := r.File[0].Open()
+			if err != nil {
+				return false
+			}
+			defer f.Close()
+			_, err = io.Copy(io.Discard, f)
+			return err == nil
+		},
+	},
+	{
+		Name: "gzip",
+		// deflate, and the 4 byte input size at the end is under 16 MiB
+		Cribs: []Crib{{0, []byte("\x1f\x8b\x08")}, {-1, []byte("\x00")}},
+		Check: func(pt []byte) bool {
+			r, err := gzip.NewReader(bytes.NewReader(pt))
+			if err != nil {
+				return false
+			}
+			_, err = io.Copy(io.Discard, r)
+			return err == nil
+		},
+	},
+	{
+		Name: "elf",
+		// version 1, System V ABI, padding
+		Cribs: []Crib{{0, []byte("\x7fELF")}, {6, []byte("\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00")}},
+		Check: func(pt []byte) bool {
+			_, err := elf.NewFile(bytes.NewReader(pt))
+			return err == nil
+		},
+	},
+	{
+		Name:  "pdf",
+		Cribs: []Crib{{0, []byte("%PDF-1.")}},
+		Variants: [][]Crib{
+			{{-6, []byte("%%EOF\n")}},
+			{{-7, []byte("%%EOF\r\n")}},
+			{{-5, []byte("%%EOF")}},
+		},
+		// the weakest cribs, so the last type tried
+		Check: pdfStructure,
+	},
+}
+
+// pdfStructure reports whether pt looks like a PDF from end to end: it
+// ends in %%EOF, has as many obj as endobj, and outside the streams every
+// line is printable text or a comment, which may hold binary bytes
+func pdfStructure(pt []byte) bool {
+	if !bytes.HasSuffix(bytes.TrimRight(pt, "\r\n"), []byte("%%EOF")) {
+		return false
+	}
+
+	// drop the stream contents, which are binary
+	var text []byte
+	for rest := pt; ; {
+		i := bytes.Index(rest, []byte("stream"))
+		for i > 0 && bytes.HasSuffix(rest[:i], []byte("end")) {
+			j := bytes.Index(rest[i+6:], []byte("stream"))
+			if j < 0 {
+				i = -1
+				break
+			}
+			i += 6 + j
+		}
+		if i < 0 {
+			text = append(text, rest...)
+			break
+		}
+		text = append(text, rest[:i]...)
+		end := bytes.Index(rest[i:], []byte("endstream"))
+		if end < 0 {
+			return false
+		}
+		rest = rest[i+end+len("endstream"):]
+	}
+
+	for _, line := range bytes.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if line[0] == '%' {
+			continue
+		}
+		for _, c := range line {
+			if (c < 0x20 || c >= 0x7f) && c != '\t' {
+				return false
+			}
+		}
+	}
+
+	objs := bytes.Count(text, []byte(" obj"))
+	return objs > 0 && objs == bytes.Count(text, []byte("endobj"))
+}
+
+// KnownPlaintextOptions tunes BreakKnownPlaintext
+type KnownPlaintextOptions struct {
+	MinKeySize int // smallest key size to consider
+	MaxKeySize int // largest key size to consider
+	// Types are the formats to try (FileTypes when nil)
+	Types []FileType
+}
+
+// DefaultKnownPlaintextOptions holds the key sizes BreakKnownPlaintext
+// uses for the fields left zero
+var DefaultKnownPlaintextOptions = KnownPlaintextOptions{MinKeySize: 1, MaxKeySize: 64}
+
+// KnownPlaintextResult is the outcome of BreakKnownPlaintext
+type KnownPlaintextResult struct {
+	Key       []byte
+	PlainText []byte
+	FileType  string // name of the file type that matched
+	Confirmed int    // key bytes more than one crib byte agreed on
+}
+
+// BreakKnownPlaintext recovers the repeating key of a file encrypted
+// with it when the file is of one of opts.Types. For every key size the
+// cribs of the type give away the key bytes under them, and must agree
+// with each other where they fall on the same key byte. The key bytes no
+// crib covers are searched column by column as in FindKeyByTransposing.
+// The first key, by type and then size, whose decryption passes the
+// check of the type wins.
+func BreakKnownPlaintext(cipherText []byte, opts KnownPlaintextOptions) (KnownPlaintextResult, error) {
+	opts.MinKeySize = cmp.Or(opts.MinKeySize, DefaultKnownPlaintextOptions.MinKeySize)
+	opts.MaxKeySize = cmp.Or(opts.MaxKeySize, DefaultKnownPlaintextOptions.MaxKeySize)
+	types := opts.Types
+	if types == nil {
+		types = FileTypes
+	}
+
+	english, err := score.Builtin("english")
+	if err != nil {
+		return KnownPlaintextResult{}, err
+	}
+	binary := score.NewBinary(english)
+
+	for _, ft := range types {
+		variants := [][]Crib{nil}
+		if len(ft.Variants) > 0 {
+			variants = ft.Variants
+		}
+		for ks := max(opts.MinKeySize, 1); ks <= opts.MaxKeySize && ks <= len(cipherText); ks++ {
+			for _, v := range variants {
+				cribs := append(slices.Clone(ft.Cribs), v...)
+				key, known, confirmed, ok := keyFromCribs(cipherText, cribs, ks)
+				if !ok || 2*count(known) < ks {
+					continue // the cribs leave too much of the key to guess
+				}
+				fillKey(cipherText, key, known, binary)
+
+				plainText := MustRepeat(cipherText, key)
+				if ft.Check == nil || ft.Check(plainText) {
+					return KnownPlaintextResult{
+						Key:       minimalPeriod(key),
+						PlainText: plainText,
+						FileType:  ft.Name,
+						Confirmed: confirmed,
+					}, nil
+				}
+			}
+		}
+	}
+	return KnownPlaintextResult{}, fmt.Errorf("%w: no known file type matches", ErrNoKey)
+}
+
+// keyFromCribs derives the bytes of a key of size ks the cribs fall on,
+// flagging them in known. It also returns how many key bytes more than one
+// crib byte agreed on, and false when the cribs contradict each other or
+// don't fit in the ciphertext.
+func keyFromCribs(cipherText []byte, cribs []Crib, ks int) (key []byte, known []bool, confirmed int, ok bool) {
+	key = make([]byte, ks)
+	known = make([]bool, ks)
+	agreed := make([]bool, ks)
+	for _, c := range cribs {
+		offset := c.Offset
+		if offset < 0 {
+			offset += len(cipherText)
+		}
+		if offset < 0 || offset+len(c.Bytes) > len(cipherText) {
+			return nil, nil, 0, false
+		}
+		for i, b := range c.Bytes {
+			p := offset + i
+			k := cipherText[p] ^ b
+			switch {
+			case !known[p%ks]:
+				key[p%ks], known[p%ks] = k, true
+			case key[p%ks] != k:
+				return nil, nil, 0, false
+			case !agreed[p%ks]:
+				agreed[p%ks] = true
+				confirmed++
+			}
+		}
+	}
+	return key, known, confirmed, true
+}
+
+// count returns how many of flags are set
+func count(flags []bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+// fillSample bounds the ciphertext fillKey looks at
+const fillSample = 2048
+
+// fillKey searches the key bytes the cribs left unknown breaking their
+// columns as single-byte XOR with s
+func fillKey(cipherText, key []byte, known []bool, s score.Scorer) {
+	sample := cipherText[:min(len(cipherText), fillSample)]
+	var column []byte
+	for i := range key {
+		if known[i] {
+			continue
+		}
+		column = column[:0]
+		for j := i; j < len(sample); j += len(key) {
+			column = append(column, sample[j])
+		}
+		key[i] = BreakSingleByteTop(column, s, 1).Best().Key
+	}
+}
@@ -0,0 +1,161 @@
+package xor
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/data"
+)
+
+// fileSamples builds a small file of every known type
+func fileSamples(t *testing.T) map[string][]byte {
+	t.Helper()
+	lyrics := data.MustReadFile("set1/output6")
+	samples := map[string][]byte{}
+
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for x := range 32 {
+		for y := range 32 {
+			img.Set(x, y, color.RGBA{uint8(8 * x), uint8(8 * y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	samples["png"] = bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("lyrics.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write(lyrics)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	samples["zip"] = bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	gw := gzip.NewWriter(&buf)
+	gw.Write(lyrics)
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	samples["gzip"] = bytes.Clone(buf.Bytes())
+
+	samples["pdf"] = []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n" +
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>\nendobj\n" +
+		"4 0 obj\n<< /Length 44 >>\nstream\nBT /F1 24 Tf 100 700 Td (Hello there) Tj ET\nendstream\nendobj\n" +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	if exe, err := os.Executable(); err == nil {
+		if f, err := elf.Open(exe); err == nil {
+			f.Close()
+			samples["elf"] = codec.MustReadFile(exe)
+		}
+	}
+	return samples
+}
+
+func TestBreakKnownPlaintext(t *testing.T) {
+	short, medium, long := []byte("k3y"), []byte("\x00\xffSECRET\x01"), []byte("sixteen byte key")
+	// the keys the cribs of each type cover well enough
+	keys := map[string][][]byte{
+		"png":  {short, medium, long},
+		"zip":  {short, medium},
+		"elf":  {short, medium, long},
+		"gzip": {short},
+		"pdf":  {short, medium},
+	}
+	for name, plainText := range fileSamples(t) {
+		for _, key := range keys[name] {
+			result, err := BreakKnownPlaintext(MustRepeat(plainText, key), KnownPlaintextOptions{})
+			if err != nil {
+				t.Errorf("%s, key %q: %v", name, key, err)
+				continue
+			}
+			if result.FileType != name || !bytes.Equal(result.Key, key) {
+				t.Errorf("%s, key %q: got %s, key %q", name, key, result.FileType, result.Key)
+			}
+		}
+	}
+
+	t.Run("PDF endings", func(t *testing.T) {
+		pdf := bytes.TrimSuffix(fileSamples(t)["pdf"], []byte("\n"))
+		for _, eol := range []string{"\n", "\r\n", ""} {
+			plainText := append(bytes.Clone(pdf), eol...)
+			for _, key := range [][]byte{short, medium} {
+				result, err := BreakKnownPlaintext(MustRepeat(plainText, key), KnownPlaintextOptions{})
+				if err != nil {
+					t.Errorf("%q ending, key %q: %v", eol, key, err)
+					continue
+				}
+				if !bytes.Equal(result.Key, key) || !bytes.Equal(result.PlainText, plainText) {
+					t.Errorf("%q ending, key %q: got key %q", eol, key, result.Key)
+				}
+			}
+		}
+	})
+
+	t.Run("Custom types", func(t *testing.T) {
+		// the gzip cribs, under another name
+		var mine FileType
+		for _, ft := range FileTypes {
+			if ft.Name == "gzip" {
+				mine = ft
+			}
+		}
+		mine.Name = "mine"
+
+		opts := KnownPlaintextOptions{Types: []FileType{mine}}
+		result, err := BreakKnownPlaintext(MustRepeat(fileSamples(t)["gzip"], short), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.FileType != "mine" || !bytes.Equal(result.Key, short) {
+			t.Errorf("got %s, key %q", result.FileType, result.Key)
+		}
+	})
+
+	t.Run("Unknown content", func(t *testing.T) {
+		random := make([]byte, 4096)
+		rand.New(rand.NewSource(1)).Read(random)
+		lyrics := data.MustReadFile("set1/output6")
+		for _, plainText := range [][]byte{random, lyrics} {
+			if _, err := BreakKnownPlaintext(MustRepeat(plainText, []byte("key")), KnownPlaintextOptions{}); !errors.Is(err, ErrNoKey) {
+				t.Errorf("expected ErrNoKey, got %v", err)
+			}
+		}
+	})
+}
+
+func TestKeyFromCribs(t *testing.T) {
+	cipherText := MustRepeat([]byte("0123456789"), []byte("ab"))
+	cribs := []Crib{{0, []byte("01")}, {-2, []byte("89")}}
+
+	key, known, confirmed, ok := keyFromCribs(cipherText, cribs, 2)
+	if !ok || string(key) != "ab" || !known[0] || !known[1] || confirmed != 2 {
+		t.Errorf("got %q %v %d %v", key, known, confirmed, ok)
+	}
+	// 9 lands on the first byte of a 3 byte key, but it was XORed with b
+	if _, _, _, ok := keyFromCribs(cipherText, cribs, 3); ok {
+		t.Errorf("expected the cribs to contradict each other")
+	}
+	key, known, _, ok = keyFromCribs(cipherText, cribs[:1], 5)
+	if !ok || known[2] || string(key[:2]) != "ab" {
+		t.Errorf("got %q %v %v", key, known, ok)
+	}
+}
@@ -0,0 +1,102 @@
+package xor
+
+import (
+	"math"
+	"slices"
+
+	"github.com/drio/cryptopals/score"
+)
+
+// AmbiguityThreshold is the relative margin under which a single-byte
+// XOR winner is considered ambiguous (see SingleByteResult.Ambiguous)
+var AmbiguityThreshold = 0.05
+
+// Candidate is a single-byte XOR key with the plaintext it produces
+type Candidate struct {
+	Key       byte
+	Score     float64
+	PlainText []byte
+}
+
+// SingleByteResult holds the best candidate keys of a single-byte XOR
+// break, best first
+type SingleByteResult struct {
+	Candidates []Candidate
+	// Margin is the score difference between the two best candidates
+	Margin float64
+}
+
+// Best returns the winning candidate
+func (r SingleByteResult) Best() Candidate {
+	return r.Candidates[0]
+}
+
+// Confidence returns the margin relative to the best score. Values close
+// to 0 mean the runner-up is almost as good as the winner.
+func (r SingleByteResult) Confidence() float64 {
+	best := math.Abs(r.Best().Score)
+	if best == 0 || math.IsInf(r.Margin, 1) {
+		return math.Inf(1)
+	}
+	return r.Margin / best
+}
+
+// Ambiguous reports whether the winner does not clearly beat the
+// runner-up
+func (r SingleByteResult) Ambiguous() bool {
+	return r.Confidence() < AmbiguityThreshold
+}
+
+// BreakSingleByteTop tries the 256 possible keys against input and
+// returns the n best (at least one) ranked with s (score.English when
+// nil). There is no readability check, so it also works for binary-ish
+// plaintexts given a suitable scorer such as score.NewBinary.
+func BreakSingleByteTop(input []byte, s score.Scorer, n int) SingleByteResult {
+	s = scorerOrDefault(s)
+	n = min(max(n, 1), 256)
+
+	var scores [256]float64
+	buf := make([]byte, len(input))
+	for k := range 256 {
+		repeat(buf, input, []byte{byte(k)}, 0)
+		scores[k] = s.Score(buf)
+	}
+
+	keys := make([]int, 256)
+	for k := range keys {
+		keys[k] = k
+	}
+	// stable, so ties go to the lower key
+	slices.SortStableFunc(keys, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return 0
+	})
+
+	r := SingleByteResult{Candidates: make([]Candidate, n)}
+	for i, k := range keys[:n] {
+		r.Candidates[i] = Candidate{byte(k), scores[k], MustRepeat(input, []byte{byte(k)})}
+	}
+	r.Margin = scores[keys[0]] - scores[keys[1]]
+	if math.IsNaN(r.Margin) {
+		r.Margin = 0 // both -Inf
+	}
+	return r
+}
+
+// BreakSingleByte returns, given a chunk of bytes, the key that scores
+// best and its score
+func BreakSingleByte(input []byte) (float64, byte) {
+	return BreakSingleByteWith(input, score.English)
+}
+
+// BreakSingleByteWith is like BreakSingleByte but rates the candidate
+// plaintexts with s
+func BreakSingleByteWith(input []byte, s score.Scorer) (float64, byte) {
+	best := BreakSingleByteTop(input, s, 1).Best()
+	return best.Score, best.Key
+}
@@ -0,0 +1,108 @@
+package xor
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"io"
+)
+
+// NewStreamAt returns a Stream for key that starts offset bytes into the
+// key stream, to pick up a file in the middle
+func NewStreamAt(key []byte, offset int64) (*Stream, error) {
+	s, err := NewStream(key)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("xor: negative offset %d", offset)
+	}
+	s.pos = int(offset % int64(len(key)))
+	return s, nil
+}
+
+// NewReader returns a reader that XORs what it reads from r with the
+// repeating key, the first byte read being at offset in the key stream
+func NewReader(r io.Reader, key []byte, offset int64) (io.Reader, error) {
+	s, err := NewStreamAt(key, offset)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.StreamReader{S: s, R: r}, nil
+}
+
+// NewWriter returns a writer that XORs what it is given with the
+// repeating key before writing it to w, the first byte written being at
+// offset in the key stream. Closing it closes w when w is an io.Closer.
+func NewWriter(w io.Writer, key []byte, offset int64) (io.WriteCloser, error) {
+	s, err := NewStreamAt(key, offset)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.StreamWriter{S: s, W: w}, nil
+}
+
+// sample is a chunk of a larger ciphertext and where it starts
+type sample struct {
+	offset int64
+	data   []byte
+}
+
+// sampleColumns returns, for every position of a key of size ks, the
+// sample bytes encrypted with it
+func sampleColumns(samples []sample, ks int) [][]byte {
+	columns := make([][]byte, ks)
+	for _, s := range samples {
+		col := int(s.offset % int64(ks))
+		for _, b := range s.data {
+			columns[col] = append(columns[col], b)
+			if col++; col == ks {
+				col = 0
+			}
+		}
+	}
+	return columns
+}
+
+// SampleOptions tunes BreakRepeatingKeyXORSampled
+type SampleOptions struct {
+	Samples    int // how many chunks to read, spread across the input
+	SampleSize int // bytes per chunk
+
+	BreakOptions
+}
+
+// DefaultSampleOptions read 1 MiB of the input at most
+var DefaultSampleOptions = SampleOptions{
+	Samples:      16,
+	SampleSize:   64 << 10,
+	BreakOptions: DefaultBreakOptions,
+}
+
+// BreakRepeatingKeyXORSampled recovers the repeating key of the size
+// bytes of r reading only opts.Samples chunks of it, so huge captures
+// don't have to fit in memory. The key sizes are ranked on the first
+// chunk and the key bytes searched on all of them. PlainText holds the
+// decrypted chunks; stream the rest through NewReader.
+func BreakRepeatingKeyXORSampled(r io.ReaderAt, size int64, opts SampleOptions) (BreakResult, error) {
+	n := max(opts.Samples, 1)
+	sampleSize := int64(max(opts.SampleSize, 1))
+	if size <= int64(n)*sampleSize {
+		// small enough to read whole
+		n, sampleSize = 1, size
+	}
+
+	samples := make([]sample, n)
+	for i := range samples {
+		offset := int64(0)
+		if n > 1 {
+			offset = int64(i) * (size - sampleSize) / int64(n-1)
+		}
+		data := make([]byte, sampleSize)
+		m, err := r.ReadAt(data, offset)
+		if err != nil && err != io.EOF {
+			return BreakResult{}, fmt.Errorf("xor: reading sample at %d: %w", offset, err)
+		}
+		samples[i] = sample{offset, data[:m]}
+	}
+	return breakSamples(samples, opts.BreakOptions)
+}
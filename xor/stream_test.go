@@ -0,0 +1,136 @@
+package xor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/drio/cryptopals/data"
+)
+
+// countingReaderAt counts the bytes read through it
+type countingReaderAt struct {
+	r    io.ReaderAt
+	read atomic.Int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func TestStreamingXOR(t *testing.T) {
+	key := []byte("Terminator X: Bring the noise")
+	plainText := data.MustReadFile("set1/output6")
+	cipherText := MustRepeat(plainText, key)
+
+	t.Run("Writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, key, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rest := plainText; len(rest) > 0; {
+			n := min(len(rest), 77)
+			if _, err := w.Write(rest[:n]); err != nil {
+				t.Fatal(err)
+			}
+			rest = rest[n:]
+		}
+		if !bytes.Equal(buf.Bytes(), cipherText) {
+			t.Errorf("wrong ciphertext")
+		}
+	})
+
+	t.Run("Reader at an offset", func(t *testing.T) {
+		for _, offset := range []int64{0, 1, 28, 29, 1000} {
+			r, err := NewReader(bytes.NewReader(cipherText[offset:]), key, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil || !bytes.Equal(got, plainText[offset:]) {
+				t.Errorf("offset %d: wrong plaintext (%v)", offset, err)
+			}
+		}
+	})
+
+	t.Run("Bad arguments", func(t *testing.T) {
+		if _, err := NewReader(nil, nil, 0); err != ErrEmptyKey {
+			t.Errorf("expected ErrEmptyKey, got %v", err)
+		}
+		if _, err := NewWriter(nil, key, -1); err == nil {
+			t.Errorf("expected an error for a negative offset")
+		}
+	})
+}
+
+func TestBreakRepeatingKeyXORSampled(t *testing.T) {
+	key := []byte("a rather long key, for a capture")
+
+	// 4 MiB of shuffled lyrics, streamed to disk
+	lines := strings.SplitAfter(string(data.MustReadFile("set1/output6")), "\n")
+	fn := filepath.Join(t.TempDir(), "capture.bin")
+	f, err := os.Create(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bw := bufio.NewWriter(f)
+	w, err := NewWriter(bw, key, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for size := 0; size < 4<<20; {
+		n, err := io.WriteString(w, lines[rng.Intn(len(lines))])
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += n
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = os.Open(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &countingReaderAt{r: f}
+	opts := DefaultSampleOptions
+	opts.Samples, opts.SampleSize = 4, 8<<10
+	result, err := BreakRepeatingKeyXORSampled(r, info.Size(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result.Key, key) {
+		t.Errorf("wrong key %q", result.Key)
+	}
+	if read := r.read.Load(); read > 4*8<<10 {
+		t.Errorf("read %d bytes, expected at most %d", read, 4*8<<10)
+	}
+
+	t.Run("Small input", func(t *testing.T) {
+		cipherText := MustRepeat(data.MustReadFile("set1/output6"), []byte("ICE"))
+		result, err := BreakRepeatingKeyXORSampled(bytes.NewReader(cipherText), int64(len(cipherText)), DefaultSampleOptions)
+		if err != nil || string(result.Key) != "ICE" {
+			t.Errorf("wrong key %q (%v)", result.Key, err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+// Package xor implements the XOR primitives (fixed and repeating-key) and
+// the analysis needed to break single-byte and repeating-key XOR.
+package xor
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrLengthMismatch is returned when two inputs must have the same
+	// length and they don't
+	ErrLengthMismatch = errors.New("xor: inputs must be of equal length")
+	// ErrEmptyKey is returned when a repeating key has no bytes
+	ErrEmptyKey = errors.New("xor: empty key")
+	// ErrNoKey is returned when no candidate key produces readable text
+	ErrNoKey = errors.New("xor: no key produces readable text")
+)
+
+// Bytes XORs a and b, which must have the same length
+func Bytes(a, b []byte) ([]byte, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("%w: %d != %d", ErrLengthMismatch, len(a), len(b))
+	}
+
+	result := make([]byte, len(a))
+	xorWords(result, a, b)
+	return result, nil
+}
+
+// MustBytes is like Bytes but panics on error
+func MustBytes(a, b []byte) []byte {
+	result, err := Bytes(a, b)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Hex runs xor on a and b.
+// a and b have to be hex encoded
+func Hex(a string, b string) (string, error) {
+	// Step 1: hex decode and get the bytes for the inputs
+	aBytes, err := hex.DecodeString(a)
+	if err != nil {
+		return "", fmt.Errorf("xor: decoding a: %w", err)
+	}
+
+	bBytes, err := hex.DecodeString(b)
+	if err != nil {
+		return "", fmt.Errorf("xor: decoding b: %w", err)
+	}
+
+	result, err := Bytes(aBytes, bBytes)
+	if err != nil {
+		return "", err
+	}
+
+	// Step 3: convert to hex
+	return hex.EncodeToString(result), nil
+}
+
+// Repeat applies a key that is smaller than the input chunk by XORing
+// repeatedly
+func Repeat(cipherText, key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+
+	plainText := make([]byte, len(cipherText))
+	repeat(plainText, cipherText, key, 0)
+	return plainText, nil
+}
+
+// MustRepeat is like Repeat but panics on error
+func MustRepeat(cipherText, key []byte) []byte {
+	result, err := Repeat(cipherText, key)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// RepeatHex applies the repeating-key XOR rkey against the bytes of
+// plainText and returns the hex representation of the encrypted result
+func RepeatHex(plainText, rkey string) (string, error) {
+	cipherText, err := Repeat([]byte(plainText), []byte(rkey))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(cipherText), nil
+}
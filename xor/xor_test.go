@@ -0,0 +1,611 @@
+package xor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"math"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/drio/cryptopals/codec"
+	"github.com/drio/cryptopals/data"
+	"github.com/drio/cryptopals/score"
+)
+
+func TestSet1Challenge02(t *testing.T) {
+	t.Run("Fixed XOR with Bytes", func(t *testing.T) {
+		one := codec.MustHexToBytes(`1c0111001f010100061a024b53535009181c`)
+		two := codec.MustHexToBytes(`686974207468652062756c6c277320657965`)
+		expect := `746865206b696420646f6e277420706c6179`
+
+		got := hex.EncodeToString(MustBytes(one, two))
+		if got != expect {
+			t.Errorf("wrong xor inputs %s %s\ngot %s\nexpected: %s", one, two, got, expect)
+		}
+	})
+
+	t.Run("Fixed XOR with Strings", func(t *testing.T) {
+		input1 := "1c0111001f010100061a024b53535009181c"
+		input2 := "686974207468652062756c6c277320657965"
+		expected := "746865206b696420646f6e277420706c6179"
+
+		result, err := Hex(input1, input2)
+		if err != nil || result != expected {
+			t.Errorf("Expected %s, but got %s", expected, result)
+		}
+	})
+}
+
+func TestSet1Challenge03(t *testing.T) {
+	t.Run("Single Byte XOR Cipher", func(t *testing.T) {
+		inputHex := `1b37373331363f78151b7f2b783431333d78397828372d363c78373e783a393b3736`
+		_, bestKey := BreakSingleByte(codec.MustHexToBytes(inputHex))
+		gotKeyHex := string(bestKey)
+		expect := `X`
+
+		if gotKeyHex != expect {
+			t.Errorf("wrong key for plaintext\n%s\ngot %s\nexpected: %s", inputHex, gotKeyHex, expect)
+		}
+	})
+}
+
+func TestSet1Challenge04(t *testing.T) {
+	t.Run("Detect Single-Character XOR", func(t *testing.T) {
+		bestScore := 0.0
+		bestPlainText := ""
+		lines, err := data.Lines("set1/4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range lines {
+			cipherTextBytes, err := codec.HexToBytes(line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if score, key := BreakSingleByte(cipherTextBytes); score > bestScore {
+				bestPlainText = string(MustRepeat(cipherTextBytes, []byte{key}))
+				bestScore = score
+			}
+		}
+
+		expect := "Now that the party is jumping\n"
+		if bestPlainText != expect {
+			t.Errorf("\nexpected:\n%s\ngot:\n%s", expect, bestPlainText)
+		}
+	})
+}
+
+func TestSet1Challenge05(t *testing.T) {
+	t.Run("Implement Repeating-Key XOR", func(t *testing.T) {
+		stanza := `Burning 'em, if you ain't quick and nimble
+I go crazy when I hear a cymbal`
+
+		plainTextHex, err := RepeatHex(stanza, "ICE")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := `0b3637272a2b2e63622c2e69692a23693a2a3c6324202d623d63343c2a26226324272765272a282b2f20430a652e2c652a3124333a653e2b2027630c692b20283165286326302e27282f`
+
+		if plainTextHex != expect {
+			t.Errorf("\nexpected:\n%s\ngot:\n%s", expect, plainTextHex)
+		}
+	})
+}
+
+func TestSet1Challenge06(t *testing.T) {
+	t.Run("Break Repeating-Key XOR", func(t *testing.T) {
+		cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+		keySize := 29
+		key, err := FindKeyByTransposing(cipherBytes, keySize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plainTextBytes := MustRepeat(cipherBytes, []byte(key))
+
+		expect := data.MustReadFile("set1/output6")
+		if string(plainTextBytes) != string(expect) {
+			t.Errorf("the plaintext does not match set1/output6\n")
+		}
+	})
+}
+
+func TestHammingDistance(t *testing.T) {
+	t.Run("Compute Hamming Distance", func(t *testing.T) {
+		input1 := "this is a test"
+		input2 := "wokka wokka!!!"
+		expected := 37
+
+		result, err := Hamming(input1, input2)
+		if err != nil || result != expected {
+			t.Errorf("Expected %d, but got %d", expected, result)
+		}
+	})
+}
+
+func TestXORErrors(t *testing.T) {
+	if _, err := Bytes([]byte("ab"), []byte("a")); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	if _, err := Hamming("ab", "a"); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+	if _, err := Repeat([]byte("ab"), nil); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("expected ErrEmptyKey, got %v", err)
+	}
+	if _, err := Hex("zz", "00"); err == nil {
+		t.Errorf("expected an error for invalid hex")
+	}
+}
+
+func TestComputeBlockHD(t *testing.T) {
+	t.Run("Normal Cases", func(t *testing.T) {
+		testCases := []struct {
+			data     []byte
+			keySize  int
+			expected float64
+			pairs    float64
+		}{
+			{[]byte("one one one one"), 4, 0, 2},
+			{[]byte("aaaabbbbaaaaaaaa"), 4, 16, 3}, // the last pair fills the text
+			{[]byte("one two tres cuatro"), 4, 25, 3},
+			{[]byte("abcdabce"), 4, 1, 1}, // a single pair
+		}
+
+		for _, tc := range testCases {
+			result := ComputeBlockHD(tc.data, tc.keySize)
+			if result.SumHD != tc.expected || result.Pairs != tc.pairs {
+				t.Errorf("input %s keySize=%d should be %v/%v pairs but got %+v", tc.data, tc.keySize, tc.expected, tc.pairs, result)
+			}
+			norm := tc.expected / tc.pairs / float64(tc.keySize)
+			if got := result.Norm(tc.keySize); got != norm {
+				t.Errorf("input %s keySize=%d: expected norm %f, got %f", tc.data, tc.keySize, norm, got)
+			}
+		}
+	})
+
+	t.Run("Edge Cases", func(t *testing.T) {
+		// Test with edge cases
+		var emptyData []byte
+		result := ComputeBlockHD(emptyData, 4)
+		if result.SumHD != 0.0 || result.Pairs != 0 {
+			t.Errorf("Expected no pairs for empty data, got %+v", result)
+		}
+
+		shortData := []byte("short")
+		result = ComputeBlockHD(shortData, 10)
+		if result.SumHD != 0.0 || result.Pairs != 0 {
+			t.Errorf("Expected no pairs when keySize is larger than data length, got %+v", result)
+		}
+	})
+}
+
+func TestRankKeySizes(t *testing.T) {
+	cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+	ranked := RankKeySizes(cipherBytes, 2, 40)
+	if len(ranked) != 39 {
+		t.Fatalf("expected 39 candidates, got %d", len(ranked))
+	}
+	if ranked[0].Size != 29 {
+		t.Errorf("expected 29 to be the best key size, got %d", ranked[0].Size)
+	}
+	for i := 1; i < len(ranked); i++ {
+		if ranked[i].Score < ranked[i-1].Score {
+			t.Fatalf("candidates are not sorted: %v", ranked)
+		}
+	}
+
+	// only size 2 has two blocks to compare
+	if got := RankKeySizes([]byte("short"), 2, 40); len(got) != 1 || got[0].Size != 2 {
+		t.Errorf("expected size 2 alone for a short text, got %v", got)
+	}
+	if got := RankKeySizes([]byte("abc"), 2, 40); len(got) != 0 {
+		t.Errorf("expected no candidates for a short text, got %v", got)
+	}
+}
+
+func TestKeySizeEstimators(t *testing.T) {
+	estimators := map[string]KeySizeEstimator{
+		"hamming":         RankKeySizes,
+		"all pairs":       RankKeySizesAllPairs,
+		"kasiski":         RankKeySizesKasiski,
+		"ic":              RankKeySizesIC,
+		"autocorrelation": RankKeySizesAutocorrelation,
+		"combined":        rankKeySizesCombined,
+	}
+	plainText := data.MustReadFile("set1/output6")
+
+	t.Run("Challenge 6", func(t *testing.T) {
+		cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+		for name, estimate := range estimators {
+			if ranked := estimate(cipherBytes, 2, 40); len(ranked) == 0 || ranked[0].Size != 29 {
+				t.Errorf("%s: expected 29 first, got %v", name, ranked)
+			}
+		}
+	})
+
+	t.Run("Divisors before multiples", func(t *testing.T) {
+		cipherBytes := MustRepeat(plainText[:2000], []byte("ICE"))
+		for name, estimate := range estimators {
+			if name == "hamming" || name == "all pairs" {
+				continue // they have no preference
+			}
+			if ranked := estimate(cipherBytes, 2, 40); ranked[0].Size != 3 {
+				t.Errorf("%s: expected 3 first, got %v", name, ranked[:3])
+			}
+		}
+	})
+
+	t.Run("Short ciphertext, long key", func(t *testing.T) {
+		cipherBytes := MustRepeat(plainText[:200], []byte("Terminator X: Bring the noise"))
+		// the Hamming distance alone picks 2 here
+		if ranked := RankKeySizesCombined(cipherBytes, 2, 40); ranked[0].Size != 29 {
+			t.Errorf("expected 29 first, got %v", ranked[:3])
+		}
+	})
+
+	t.Run("Nothing to rank", func(t *testing.T) {
+		for name, estimate := range estimators {
+			if ranked := estimate([]byte("abc"), 2, 40); len(ranked) != 0 {
+				t.Errorf("%s: expected no candidates, got %v", name, ranked)
+			}
+		}
+	})
+}
+
+func TestPairwiseHamming(t *testing.T) {
+	plainText := data.MustReadFile("set1/output6")
+	key := []byte("Terminator X: Bring the noise, the noise, the noise!")
+
+	t.Run("Short ciphertext", func(t *testing.T) {
+		// 3 blocks: the neighbours alone are not enough
+		cipherBytes := MustRepeat(plainText[:160], key)
+		if ranked := RankKeySizesAllPairs(cipherBytes, 2, 60); ranked[0].Size != len(key) {
+			t.Errorf("expected %d first, got %v", len(key), ranked[:3])
+		}
+	})
+
+	t.Run("Keys in the hundreds", func(t *testing.T) {
+		for _, kl := range []int{101, 173, 250} {
+			key := bytes.Repeat([]byte{0}, kl)
+			rand.New(rand.NewSource(int64(kl))).Read(key)
+			cipherBytes := MustRepeat(plainText, key)
+			if ranked := RankKeySizesAllPairs(cipherBytes, 2, 300); ranked[0].Size != kl {
+				t.Errorf("all pairs: expected %d first, got %v", kl, ranked[:3])
+			}
+			if ranked := RankKeySizesCombined(cipherBytes, 2, 300); ranked[0].Size != kl {
+				t.Errorf("combined: expected %d first, got %v", kl, ranked[:3])
+			}
+		}
+	})
+
+	t.Run("Sampled", func(t *testing.T) {
+		cipherBytes := MustRepeat(plainText, key)
+		all := PairwiseHamming(0)(cipherBytes, 40, 60)
+		sampled := PairwiseHamming(500)(cipherBytes, 40, 60)
+		if all[0].Size != len(key) || sampled[0].Size != len(key) {
+			t.Errorf("expected %d first, got %v and %v", len(key), all[0], sampled[0])
+		}
+		if again := PairwiseHamming(500)(cipherBytes, 40, 60); again[0] != sampled[0] {
+			t.Errorf("the sample is not reproducible")
+		}
+	})
+}
+
+func TestHammingBytes(t *testing.T) {
+	a := []byte("this is a test, and a much longer one than wokka wokka!!!")
+	b := []byte("wokka wokka!!!, and a much longer one than this is a test")
+	for n := range len(a) {
+		d, err := HammingBytes(a[:n], b[:n])
+		want := 0
+		for i := range n {
+			for x := a[i] ^ b[i]; x != 0; x &= x - 1 {
+				want++
+			}
+		}
+		if err != nil || d != want {
+			t.Fatalf("len %d: expected %d, got %d (%v)", n, want, d, err)
+		}
+	}
+	if _, err := HammingBytes(a, b[1:]); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestIndexOfCoincidence(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected float64
+	}{
+		{"", 0},
+		{"a", 0},
+		{"aaaa", 1},
+		{"abcd", 0},
+		{"aabb", 1.0 / 3},
+	}
+	for _, tc := range cases {
+		if ic := indexOfCoincidence([]byte(tc.input)); math.Abs(ic-tc.expected) > 1e-9 {
+			t.Errorf("%q: expected %f, got %f", tc.input, tc.expected, ic)
+		}
+	}
+}
+
+func TestBreakRepeatingKeyXOR(t *testing.T) {
+	t.Run("Challenge 6", func(t *testing.T) {
+		cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+		result, err := BreakRepeatingKeyXOR(cipherBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result.Key) != "Terminator X: Bring the noise" {
+			t.Errorf("wrong key %q", result.Key)
+		}
+		expect := data.MustReadFile("set1/output6")
+		if string(result.PlainText) != string(expect) {
+			t.Errorf("the plaintext does not match set1/output6\n")
+		}
+	})
+
+	t.Run("Not a multiple of the key", func(t *testing.T) {
+		// a multiple of the key size fits every column and once took the
+		// win with a key 2 to 4 times too long
+		plainText := data.MustReadFile("set1/output6")
+		rng := rand.New(rand.NewSource(1))
+		for _, size := range []int{7, 10, 11, 19, 20} {
+			for _, n := range []int{844, len(plainText)} {
+				key := make([]byte, size)
+				rng.Read(key)
+				result, err := BreakRepeatingKeyXOR(MustRepeat(plainText[:n], key))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(result.Key, key) || !bytes.Equal(result.PlainText, plainText[:n]) {
+					t.Errorf("%d byte key on %d bytes: got a %d byte key", size, n, len(result.Key))
+				}
+			}
+		}
+	})
+
+	t.Run("Too short", func(t *testing.T) {
+		if _, err := BreakRepeatingKeyXOR([]byte("abc")); !errors.Is(err, ErrNoKey) {
+			t.Errorf("expected ErrNoKey, got %v", err)
+		}
+	})
+}
+
+func TestMinimalPeriod(t *testing.T) {
+	cases := map[string]string{
+		"ICEICE": "ICE",
+		"ICE":    "ICE",
+		"aaaa":   "a",
+		"abcab":  "abcab",
+	}
+	for in, expected := range cases {
+		if got := string(minimalPeriod([]byte(in))); got != expected {
+			t.Errorf("minimalPeriod(%q) = %q, want %q", in, got, expected)
+		}
+	}
+}
+
+func TestBreakWithScorers(t *testing.T) {
+	corpus := func() fs.File {
+		f, err := data.Open("set1/output6")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Close() })
+		return f
+	}
+	chi, err := score.TrainChiSquared(corpus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigram, err := score.TrainNGram(corpus(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scorers := map[string]score.Scorer{
+		"chi-squared": chi,
+		"trigram":     trigram,
+		"words":       score.NewWordList(score.EnglishWords),
+	}
+	cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+	ch3 := codec.MustHexToBytes(`1b37373331363f78151b7f2b783431333d78397828372d363c78373e783a393b3736`)
+
+	for name, s := range scorers {
+		if _, key := BreakSingleByteWith(ch3, s); key != 'X' {
+			t.Errorf("%s: wrong single byte key %q", name, key)
+		}
+
+		if name == "words" {
+			// the transposed columns hold no words
+			continue
+		}
+		opts := DefaultBreakOptions
+		opts.Scorer = s
+		result, err := BreakRepeatingKeyXORWith(cipherBytes, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(result.Key) != "Terminator X: Bring the noise" {
+			t.Errorf("%s: wrong key %q", name, result.Key)
+		}
+	}
+}
+
+func TestBreakWithProfiles(t *testing.T) {
+	cases := []struct {
+		fn   string
+		key  string
+		lang string
+	}{
+		{"../score/testdata/spanish.txt", "Contrasena", "spanish"},
+		{"../score/testdata/french.txt", "MotDePasse!", "french"},
+		{"../score/testdata/german.txt", "Schluessel", "german"},
+		{"../score/testdata/code.json", "k3y", "code"},
+		{"../data/set1/output6.txt", "Terminator X: Bring the noise", "english"},
+	}
+
+	opts := DefaultBreakOptions
+	opts.Profiles = score.Profiles()
+	for _, tc := range cases {
+		plainText := codec.MustReadFile(tc.fn)
+		cipherText := MustRepeat(plainText, []byte(tc.key))
+
+		result, err := BreakRepeatingKeyXORWith(cipherText, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.fn, err)
+		}
+		if string(result.Key) != tc.key {
+			t.Errorf("%s: expected key %q, got %q", tc.fn, tc.key, result.Key)
+		}
+		if result.Language != tc.lang {
+			t.Errorf("%s: expected language %s, got %s", tc.fn, tc.lang, result.Language)
+		}
+
+		// single-byte XOR, with the language picked automatically
+		line := plainText[:80]
+		_, key := BreakSingleByteWith(MustRepeat(line, []byte{'K'}), score.Auto(opts.Profiles))
+		if key != 'K' {
+			t.Errorf("%s: wrong single byte key %q", tc.fn, key)
+		}
+	}
+}
+
+func TestBreakSingleByteTop(t *testing.T) {
+	plainText := []byte("Cooking MC's like a pound of bacon")
+
+	t.Run("Every key", func(t *testing.T) {
+		for k := range 256 {
+			r := BreakSingleByteTop(MustRepeat(plainText, []byte{byte(k)}), nil, 5)
+			if r.Best().Key != byte(k) || string(r.Best().PlainText) != string(plainText) {
+				t.Errorf("key %#02x: got %#02x %q", k, r.Best().Key, r.Best().PlainText)
+			}
+		}
+	})
+
+	t.Run("Ranked candidates", func(t *testing.T) {
+		r := BreakSingleByteTop(MustRepeat(plainText, []byte{0x58}), nil, 5)
+		if len(r.Candidates) != 5 {
+			t.Fatalf("expected 5 candidates, got %d", len(r.Candidates))
+		}
+		for i := 1; i < len(r.Candidates); i++ {
+			if r.Candidates[i].Score > r.Candidates[i-1].Score {
+				t.Errorf("candidates are not sorted")
+			}
+		}
+		if r.Margin != r.Candidates[0].Score-r.Candidates[1].Score || r.Margin <= 0 {
+			t.Errorf("wrong margin %f", r.Margin)
+		}
+		if r.Ambiguous() {
+			t.Errorf("expected a clear winner, confidence %f", r.Confidence())
+		}
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		// a single letter: its case flipped version is as good
+		r := BreakSingleByteTop([]byte("e"), nil, 3)
+		if !r.Ambiguous() {
+			t.Errorf("expected an ambiguous winner, confidence %f", r.Confidence())
+		}
+	})
+
+	t.Run("Binary-ish plaintext", func(t *testing.T) {
+		english, err := score.Builtin("english")
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := []byte("\x01\x00\x07user_id\x00\x00\x04\xd2\x02\x00\x0bdisplayname\x00\x00\x0e" +
+			"Alice Liddell\x03\x00\x05email\x00\x00\x11alice@example.org\x04\x00\x04role\x00\x00\x05admin")
+		for _, k := range []byte{0x00, 0x5a, 0xa7, 0xff} {
+			cipherText := MustRepeat(record, []byte{k})
+
+			r := BreakSingleByteTop(cipherText, score.NewBinary(english), 1)
+			if r.Best().Key != k {
+				t.Errorf("binary scorer, key %#02x: got %#02x", k, r.Best().Key)
+			}
+
+			// text models take NUL for space, but the real key is the
+			// runner-up
+			r = BreakSingleByteTop(cipherText, nil, 2)
+			if r.Candidates[1].Key != k {
+				t.Errorf("english scorer, key %#02x: got %#02x, %#02x", k, r.Candidates[0].Key, r.Candidates[1].Key)
+			}
+		}
+	})
+}
+
+func TestFindKeyCandidates(t *testing.T) {
+	cipherBytes := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+	columns, err := FindKeyCandidates(cipherBytes, 29, nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := []byte{}
+	for _, c := range columns {
+		if len(c.Candidates) != 3 {
+			t.Fatalf("expected 3 candidates, got %d", len(c.Candidates))
+		}
+		key = append(key, c.Best().Key)
+	}
+	if string(key) != "Terminator X: Bring the noise" {
+		t.Errorf("wrong key %q", key)
+	}
+
+	if _, err := FindKeyCandidates([]byte("abc"), 4, nil, 1); !errors.Is(err, ErrNoKey) {
+		t.Errorf("expected ErrNoKey, got %v", err)
+	}
+}
+
+// hammingBytes8 is the byte at a time popcount hammingBytes replaced
+func hammingBytes8(ba, bb []byte) int {
+	distance := 0
+	for i := range ba {
+		distance += bits.OnesCount8(ba[i] ^ bb[i])
+	}
+	return distance
+}
+
+func BenchmarkHamming(b *testing.B) {
+	x := codec.MustBase64ToBytes(string(data.MustReadFile("set1/6")))
+	y := x[1:]
+	x = x[:len(y)]
+
+	b.Run("OnesCount8", func(b *testing.B) {
+		b.SetBytes(int64(len(x)))
+		for b.Loop() {
+			hammingBytes8(x, y)
+		}
+	})
+	b.Run("OnesCount64", func(b *testing.B) {
+		b.SetBytes(int64(len(x)))
+		for b.Loop() {
+			hammingBytes(x, y)
+		}
+	})
+}
+
+func BenchmarkRankKeySizes(b *testing.B) {
+	plainText := bytes.Repeat(data.MustReadFile("set1/output6"), 1<<20/2876+1)[:1<<20]
+	cipherBytes := MustRepeat(plainText, []byte("a key in the hundreds: "+strings.Repeat("x", 150)))
+
+	b.Run("Neighbours", func(b *testing.B) {
+		for b.Loop() {
+			RankKeySizes(cipherBytes, 2, 300)
+		}
+	})
+	b.Run("AllPairsSampled", func(b *testing.B) {
+		for b.Loop() {
+			RankKeySizesAllPairs(cipherBytes, 2, 300)
+		}
+	})
+	b.Run("Combined", func(b *testing.B) {
+		for b.Loop() {
+			RankKeySizesCombined(cipherBytes, 2, 300)
+		}
+	})
+}